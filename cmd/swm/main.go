@@ -10,6 +10,7 @@ import (
 	"github.com/janbina/swm/internal/buildconfig"
 	"github.com/janbina/swm/internal/communication"
 	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/keybindings"
 	"github.com/janbina/swm/internal/windowmanager"
 )
 
@@ -17,6 +18,7 @@ func main() {
 
 	replace := flag.Bool("replace", false, "whether swm should replace current wm")
 	customConfig := flag.String("c", "", "path to swmrc file")
+	customConfigFile := flag.String("f", "", "path to config file")
 	version := flag.Bool("v", false, "print swm version")
 	flag.Parse()
 
@@ -39,11 +41,26 @@ func main() {
 		log.Fatalf("Cannot setup root window: %s", err)
 	}
 
+	keybindings.Initialize(X, communication.RunCommand)
+
+	if err := communication.LoadConfig(*customConfigFile); err != nil {
+		log.Printf("Errors in config file:\n%s", err)
+	}
+
 	go communication.Listen(X.Conn())
 
-	config.FindAndRunSwmrc(*customConfig)
+	windowmanager.RunWithCommands(func() {
+		config.FindAndRunSwmrc(*customConfig)
+	})
 
 	windowmanager.ManageExistingClients()
 
 	windowmanager.Run()
+
+	communication.Close()
+
+	if windowmanager.IsRestarting() {
+		err := windowmanager.ExecRestart()
+		log.Fatalf("Cannot restart swm: %s", err)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net"
@@ -65,4 +66,12 @@ func main() {
 	if len(reply) > 0 {
 		fmt.Println(reply)
 	}
+
+	if len(os.Args) > 1 && os.Args[1] == "subscribe" {
+		if len(reply) > 0 {
+			os.Exit(1)
+		}
+		// successfully subscribed, events are streamed until swm closes the connection
+		_, _ = io.Copy(os.Stdout, reader)
+	}
 }
@@ -2,13 +2,27 @@ package communication
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"net"
 	"os"
 	"path"
+	"sync"
 
 	"github.com/BurntSushi/xgb"
+	"github.com/janbina/swm/internal/events"
+	"github.com/janbina/swm/internal/windowmanager"
+	"github.com/mattn/go-shellwords"
+)
+
+var (
+	// replies to commands being executed, they have to be sent before swm exits or restarts
+	replies      sync.WaitGroup
+	repliesMutex sync.Mutex
+	closed       bool
 )
 
 func GetSocketFilePath(x *xgb.Conn) string {
@@ -56,11 +70,75 @@ func handleClient(conn net.Conn) {
 		}
 		msg = msg[:len(msg)-1]
 
-		out := processCommand(msg)
+		if args, _ := shellwords.Parse(msg); len(args) > 0 && args[0] == "subscribe" {
+			handleSubscription(conn, args[1:])
+			break
+		}
+
+		if !beginReply() {
+			break
+		}
+		out := "swm is shutting down"
+		windowmanager.Execute(func() {
+			out = processCommand(msg)
+		})
 
 		if _, err := fmt.Fprintf(conn, "%s%c", out, 0); err != nil {
 			log.Printf("Error sending response to swmctl: %s", err)
 		}
+		replies.Done()
 	}
 	_ = conn.Close()
 }
+
+// beginReply registers reply to be sent, returns false when no more commands are accepted
+func beginReply() bool {
+	repliesMutex.Lock()
+	defer repliesMutex.Unlock()
+	if closed {
+		return false
+	}
+	replies.Add(1)
+	return true
+}
+
+// Close stops accepting commands and waits until replies to already received commands are sent,
+// it should be called after the event loop ends, e.g. swmctl restart would get no reply otherwise
+func Close() {
+	repliesMutex.Lock()
+	closed = true
+	repliesMutex.Unlock()
+	replies.Wait()
+}
+
+// handleSubscription streams newline-delimited json events to the client until it disconnects
+// Empty reply is sent first to let the client know the subscription was successful
+func handleSubscription(conn net.Conn, names []string) {
+	s, err := events.Subscribe(names)
+	if err != nil {
+		_, _ = fmt.Fprintf(conn, "%s%c", err, 0)
+		return
+	}
+	defer events.Unsubscribe(s)
+
+	if _, err := fmt.Fprintf(conn, "%c", 0); err != nil {
+		return
+	}
+
+	// client doesn't send anything after subscribing, reading only detects that it disconnected,
+	// so it is removed right away instead of waiting for the next event
+	go func() {
+		_, _ = io.Copy(ioutil.Discard, conn)
+		events.Unsubscribe(s)
+	}()
+
+	encoder := json.NewEncoder(conn)
+	for e := range s.Events() {
+		if err := encoder.Encode(e); err != nil {
+			return
+		}
+	}
+	if s.Overflowed() {
+		log.Printf("Events were dropped, subscriber was removed because its buffer was full")
+	}
+}
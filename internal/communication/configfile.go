@@ -0,0 +1,68 @@
+package communication
+
+import (
+	"log"
+
+	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/keybindings"
+	"github.com/janbina/swm/internal/rules"
+)
+
+// custom config file path from command line, it is used again on reload
+var configCustomPath string
+
+// whether commands come from config file, rules and key bindings added by them are replaced on reload
+var applyingConfigFile bool
+
+func init() {
+	// reload runs other commands, so it cannot be in the command table initializer
+	commands["reload"] = reloadCommand
+}
+
+// LoadConfig finds config file, parses it and applies it
+// Nothing is applied if the file cannot be parsed, failing commands don't prevent others from being applied
+func LoadConfig(customPath string) error {
+	configCustomPath = customPath
+	return applyConfigFile(false)
+}
+
+// applyConfigFile applies config file, if reset is true, rules and key bindings from the file are removed before that,
+// so the ones removed from the file don't stay active, while the ones added by swmrc or swmctl are kept
+func applyConfigFile(reset bool) error {
+	path := config.FindConfigFile(configCustomPath)
+	if path == "" {
+		return nil
+	}
+	log.Printf("Applying config file \"%s\"", path)
+
+	directives, err := config.ParseConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	if reset {
+		rules.RemoveFromConfigFile()
+		keybindings.UnbindFromConfigFile()
+	}
+
+	applyingConfigFile = true
+	defer func() { applyingConfigFile = false }()
+
+	var errs config.Errors
+	for _, d := range directives {
+		if out := runCommand(d.Args); out != "" {
+			errs.Add(path, d.Line, out)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func reloadCommand(_ []string) string {
+	if err := applyConfigFile(true); err != nil {
+		return err.Error()
+	}
+	return ""
+}
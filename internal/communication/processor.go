@@ -1,32 +1,51 @@
 package communication
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/decoration"
 	"github.com/janbina/swm/internal/groupmanager"
+	"github.com/janbina/swm/internal/keybindings"
+	"github.com/janbina/swm/internal/rules"
 	"github.com/janbina/swm/internal/util"
 	"github.com/janbina/swm/internal/windowmanager"
 	"github.com/mattn/go-shellwords"
 )
 
 var commands = map[string]func([]string) string{
-	"shutdown":           shutdownCommand,
-	"move":               moveCommand,
-	"resize":             resizeCommand,
-	"moveresize":         moveResizeCommand,
-	"cycle-win":          cycleWinCommand,
-	"cycle-win-rev":      cycleWinRevCommand,
-	"cycle-win-end":      cycleWinEndCommand,
-	"begin-mouse-move":   mouseMoveCommand,
-	"begin-mouse-resize": mouseResizeCommand,
-	"config":             configCommand,
-	"group":              groupCommand,
+	"shutdown":              shutdownCommand,
+	"restart":               restartCommand,
+	"move":                  moveCommand,
+	"resize":                resizeCommand,
+	"moveresize":            moveResizeCommand,
+	"cycle-win":             cycleWinCommand,
+	"cycle-win-rev":         cycleWinRevCommand,
+	"cycle-win-end":         cycleWinEndCommand,
+	"begin-mouse-move":      mouseMoveCommand,
+	"begin-mouse-resize":    mouseResizeCommand,
+	"begin-keyboard-move":   keyboardMoveCommand,
+	"begin-keyboard-resize": keyboardResizeCommand,
+	"config":                configCommand,
+	"group":                 groupCommand,
+	"query":                 queryCommand,
+	"focus":                 focusCommand,
+	"swap":                  swapCommand,
+	"title-bar":             titleBarCommand,
+	"shade":                 shadeCommand,
+	"head":                  headCommand,
+	"rule":                  ruleCommand,
+	"bind":                  bindCommand,
+	"unbind":                unbindCommand,
+	"exec":                  execCommand,
+	"session":               sessionCommand,
 }
 
 func processCommand(msg string) string {
@@ -34,6 +53,19 @@ func processCommand(msg string) string {
 
 	args, _ := shellwords.Parse(msg)
 
+	return runCommand(args)
+}
+
+// RunCommand runs already parsed command, it has to be called on the X event loop goroutine
+// Used for key bindings, which are handled there
+func RunCommand(args []string) {
+	if out := runCommand(args); out != "" {
+		log.Printf("Command %s: %s", args, out)
+	}
+	windowmanager.IgnoreEnterEvents()
+}
+
+func runCommand(args []string) string {
 	if len(args) == 0 {
 		return printUsage("No command")
 	}
@@ -73,6 +105,11 @@ func shutdownCommand(_ []string) string {
 	return ""
 }
 
+func restartCommand(_ []string) string {
+	windowmanager.Restart()
+	return ""
+}
+
 func moveCommand(args []string) string {
 	f := flag.NewFlagSet("move", flag.ContinueOnError)
 	id := f.Int("id", 0, "")
@@ -80,11 +117,23 @@ func moveCommand(args []string) string {
 	south := f.Int("s", 0, "")
 	north := f.Int("n", 0, "")
 	east := f.Int("e", 0, "")
+	snap := f.String("snap", "", "")
 
 	if err := f.Parse(args); err != nil {
 		return fmt.Sprintf("Error parsing arguments: %s", err)
 	}
 
+	if *snap != "" {
+		dir, err := windowmanager.ParseDirection(*snap)
+		if err != nil {
+			return err.Error()
+		}
+		if err := windowmanager.SnapWindow(*id, dir); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+
 	winGeom, err := windowmanager.GetWindowGeometry(*id)
 	if err != nil {
 		return fmt.Sprintf("Cannot get active window geometry: %s", err)
@@ -153,66 +202,141 @@ func moveResizeCommand(args []string) string {
 	if err != nil {
 		return fmt.Sprintf("Cannot get active window geometry: %s", err)
 	}
-	if *x == 0 {
-		*x = int(*xr * float64(screenGeom.Width()))
-	}
 
-	if *y == 0 {
-		*y = int(*yr * float64(screenGeom.Height()))
+	g := &util.RelativeGeometry{
+		Origin: *origin,
+		X:      *x, Y: *y, W: *w, H: *h,
+		XR: *xr, YR: *yr, WR: *wr, HR: *hr,
 	}
+	realX, realY, realW, realH := g.Resolve(screenGeom, winGeom)
 
-	if *w == 0 {
-		*w = int(*wr * float64(screenGeom.Width()))
+	if err := windowmanager.MoveResizeWindow(*id, realX, realY, realW, realH); err != nil {
+		return err.Error()
 	}
+	return ""
+}
 
-	if *h == 0 {
-		*h = int(*hr * float64(screenGeom.Height()))
-	}
+func cycleWinCommand(_ []string) string {
+	windowmanager.CycleWin()
+	return ""
+}
 
-	if *w <= 0 {
-		*w = winGeom.Width()
-	}
+func cycleWinRevCommand(_ []string) string {
+	windowmanager.CycleWinRev()
+	return ""
+}
+
+func cycleWinEndCommand(_ []string) string {
+	windowmanager.CycleWinEnd()
+	return ""
+}
 
-	if *h <= 0 {
-		*h = winGeom.Height()
+func focusCommand(args []string) string {
+	f := flag.NewFlagSet("focus", flag.ContinueOnError)
+	direction := f.String("d", "", "")
+	wrap := f.Bool("wrap", false, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
 	}
 
-	var realY int
-	if strings.Contains(*origin, "n") {
-		realY = screenGeom.Y() + *y
-	} else if strings.Contains(*origin, "s") {
-		realY = screenGeom.Y() + screenGeom.Height() - *y - *h
-	} else { //center
-		realY = screenGeom.Y() + screenGeom.Height()/2 - *h/2 + *y
+	dir, err := windowmanager.ParseDirection(*direction)
+	if err != nil {
+		return err.Error()
 	}
+	if err := windowmanager.FocusDirection(dir, *wrap); err != nil {
+		return err.Error()
+	}
+	return ""
+}
 
-	var realX int
-	if strings.Contains(*origin, "w") {
-		realX = screenGeom.X() + *x
-	} else if strings.Contains(*origin, "e") {
-		realX = screenGeom.X() + screenGeom.Width() - *x - *w
-	} else { //center
-		realX = screenGeom.X() + screenGeom.Width()/2 - *w/2 + *x
+func swapCommand(args []string) string {
+	f := flag.NewFlagSet("swap", flag.ContinueOnError)
+	id := f.Int("id", 0, "")
+	direction := f.String("d", "", "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
 	}
 
-	if err := windowmanager.MoveResizeWindow(*id, realX, realY, *w, *h); err != nil {
+	dir, err := windowmanager.ParseDirection(*direction)
+	if err != nil {
+		return err.Error()
+	}
+	if err := windowmanager.SwapWindow(*id, dir); err != nil {
 		return err.Error()
 	}
 	return ""
 }
 
-func cycleWinCommand(_ []string) string {
-	windowmanager.CycleWin()
+func titleBarCommand(args []string) string {
+	f := flag.NewFlagSet("title-bar", flag.ContinueOnError)
+	id := f.Int("id", 0, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+
+	var err error
+	switch f.Arg(0) {
+	case "show":
+		err = windowmanager.SetTitleBar(*id, true)
+	case "hide":
+		err = windowmanager.SetTitleBar(*id, false)
+	case "toggle":
+		err = windowmanager.ToggleTitleBar(*id)
+	default:
+		return "Expected show, hide or toggle"
+	}
+	if err != nil {
+		return err.Error()
+	}
 	return ""
 }
 
-func cycleWinRevCommand(_ []string) string {
-	windowmanager.CycleWinRev()
+func shadeCommand(args []string) string {
+	f := flag.NewFlagSet("shade", flag.ContinueOnError)
+	id := f.Int("id", 0, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+
+	var err error
+	switch f.Arg(0) {
+	case "on":
+		err = windowmanager.SetShaded(*id, true)
+	case "off":
+		err = windowmanager.SetShaded(*id, false)
+	case "toggle":
+		err = windowmanager.ToggleShaded(*id)
+	default:
+		return "Expected on, off or toggle"
+	}
+	if err != nil {
+		return err.Error()
+	}
 	return ""
 }
 
-func cycleWinEndCommand(_ []string) string {
-	windowmanager.CycleWinEnd()
+func headCommand(args []string) string {
+	if len(args) == 0 || args[0] != "send" {
+		return "Usage: head send [-id windowId] [-warp] next|prev|<head>"
+	}
+	f := flag.NewFlagSet("head", flag.ContinueOnError)
+	id := f.Int("id", 0, "")
+	warp := f.Bool("warp", false, "")
+
+	if err := f.Parse(args[1:]); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+	if f.NArg() == 0 {
+		return "No head specified"
+	}
+
+	if err := windowmanager.SendToHead(*id, f.Arg(0), *warp); err != nil {
+		return err.Error()
+	}
 	return ""
 }
 
@@ -230,6 +354,34 @@ func mouseResizeCommand(_ []string) string {
 	return ""
 }
 
+func keyboardMoveCommand(args []string) string {
+	f := flag.NewFlagSet("begin-keyboard-move", flag.ContinueOnError)
+	id := f.Int("id", 0, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+
+	if err := windowmanager.BeginKeyboardMove(*id); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func keyboardResizeCommand(args []string) string {
+	f := flag.NewFlagSet("begin-keyboard-resize", flag.ContinueOnError)
+	id := f.Int("id", 0, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+
+	if err := windowmanager.BeginKeyboardResize(*id); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
 func configCommand(args []string) string {
 	if len(args) == 0 {
 		return "Nothing to configure"
@@ -241,30 +393,62 @@ func configCommand(args []string) string {
 			return err.Error()
 		}
 		config.SetAllBorders(s, n, ac, att)
+		windowmanager.DecorationsChanged()
 	case "border-top":
 		s, n, ac, att, err := parseBorderConfig(args[1:])
 		if err != nil {
 			return err.Error()
 		}
 		config.SetTopBorder(s, n, ac, att)
+		windowmanager.DecorationsChanged()
 	case "border-bottom":
 		s, n, ac, att, err := parseBorderConfig(args[1:])
 		if err != nil {
 			return err.Error()
 		}
 		config.SetBottomBorder(s, n, ac, att)
+		windowmanager.DecorationsChanged()
 	case "border-left":
 		s, n, ac, att, err := parseBorderConfig(args[1:])
 		if err != nil {
 			return err.Error()
 		}
 		config.SetLeftBorder(s, n, ac, att)
+		windowmanager.DecorationsChanged()
 	case "border-right":
 		s, n, ac, att, err := parseBorderConfig(args[1:])
 		if err != nil {
 			return err.Error()
 		}
 		config.SetRightBorder(s, n, ac, att)
+		windowmanager.DecorationsChanged()
+	case "border-style":
+		style, err := parseBorderStyle(args[1:])
+		if err != nil {
+			return err.Error()
+		}
+		config.SetBorderStyle(style)
+		windowmanager.DecorationsChanged()
+	case "corner-radius":
+		if len(args) < 2 {
+			return "No radius provided"
+		}
+		r, err := strconv.Atoi(args[1])
+		if err != nil || r < 0 {
+			return "Invalid radius"
+		}
+		config.CornerRadius = r
+		windowmanager.DecorationsChanged()
+	case "resize-margin":
+		if len(args) < 2 {
+			return "No margin provided"
+		}
+		m, err := strconv.Atoi(args[1])
+		if err != nil || m < 0 {
+			return "Invalid margin"
+		}
+		config.ResizeMargin = m
+		windowmanager.DecorationsChanged()
 	case "move-drag-shortcut":
 		if len(args) < 2 {
 			return "No shortcut provided"
@@ -306,6 +490,87 @@ func configCommand(args []string) string {
 		} else {
 			config.InfoBoxTextColor = uint32(color)
 		}
+	case "placement":
+		if len(args) < 2 {
+			return "No placement provided"
+		}
+		if !config.IsValidPlacement(args[1]) {
+			return "Unsupported placement"
+		}
+		config.Placement = args[1]
+	case "snap-distance":
+		if len(args) < 2 {
+			return "No distance provided"
+		}
+		d, err := strconv.Atoi(args[1])
+		if err != nil || d < 0 {
+			return "Invalid distance"
+		}
+		config.SnapDistance = d
+	case "focus-model":
+		if len(args) < 2 {
+			return "No focus model provided"
+		}
+		if !config.IsValidFocusModel(args[1]) {
+			return "Unsupported focus model"
+		}
+		config.FocusModel = args[1]
+	case "autoraise":
+		if len(args) < 2 {
+			return "No value provided"
+		}
+		enabled, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return "Invalid value"
+		}
+		config.AutoRaise = enabled
+	case "autoraise-delay":
+		if len(args) < 2 {
+			return "No delay provided"
+		}
+		d, err := strconv.Atoi(args[1])
+		if err != nil || d < 0 {
+			return "Invalid delay"
+		}
+		config.AutoRaiseDelay = d
+	case "edge-tiling":
+		if len(args) < 2 {
+			return "No value provided"
+		}
+		enabled, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return "Invalid value"
+		}
+		config.EdgeTiling = enabled
+	case "title-bar":
+		if len(args) < 2 {
+			return "No value provided"
+		}
+		enabled, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return "Invalid value"
+		}
+		config.TitleBar = enabled
+	case "title-bar-height":
+		if len(args) < 2 {
+			return "No height provided"
+		}
+		h, err := strconv.Atoi(args[1])
+		if err != nil || h <= 0 {
+			return "Invalid height"
+		}
+		config.TitleBarHeight = h
+		windowmanager.DecorationsChanged()
+	case "title-bar-text-color":
+		if len(args) < 2 {
+			return "No color provided"
+		}
+		color, err := hex2int(args[1])
+		if err != nil {
+			return "Invalid color"
+		}
+		config.TitleBarTextColor = uint32(color)
+		windowmanager.DecorationsChanged()
 	default:
 		return "Unsupported config argument"
 	}
@@ -336,6 +601,20 @@ func groupCommand(args []string) string {
 		if id, err := strconv.Atoi(args[1]); err != nil {
 			return "Invalid group id"
 		} else {
+			f := flag.NewFlagSet("group", flag.ContinueOnError)
+			head := f.String("head", "", "")
+			if err := f.Parse(args[2:]); err != nil {
+				return fmt.Sprintf("Error parsing arguments: %s", err)
+			}
+			if *head != "" {
+				if args[0] != "show" && args[0] != "only" {
+					return fmt.Sprintf("Head cannot be specified for group %s", args[0])
+				}
+				if err := windowmanager.ShowGroupOnHead(id, *head, args[0] == "only"); err != nil {
+					return err.Error()
+				}
+				return ""
+			}
 			switch args[0] {
 			case "toggle":
 				windowmanager.ToggleGroupVisibility(id)
@@ -349,6 +628,25 @@ func groupCommand(args []string) string {
 				panic("Unreachable")
 			}
 		}
+	case "per-head":
+		if len(args) < 2 {
+			return "Usage: group per-head on|off"
+		}
+		switch args[1] {
+		case "on":
+			windowmanager.SetGroupsPerHead(true)
+		case "off":
+			windowmanager.SetGroupsPerHead(false)
+		default:
+			return "Usage: group per-head on|off"
+		}
+	case "swap":
+		if len(args) < 3 {
+			return "Usage: group swap <head> <head>"
+		}
+		if err := windowmanager.SwapHeads(args[1], args[2]); err != nil {
+			return err.Error()
+		}
 	case "set", "add", "remove":
 		f := flag.NewFlagSet("wingroup", flag.ContinueOnError)
 		id := f.Int("id", 0, "")
@@ -373,6 +671,37 @@ func groupCommand(args []string) string {
 		if err := fun(*id, *group); err != nil {
 			return err.Error()
 		}
+	case "layout", "master-count", "master-ratio":
+		if len(args) < 3 {
+			return fmt.Sprintf("Usage: group %s <groupId> <value>", args[0])
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "Invalid group id"
+		}
+		// values prefixed with sign are relative to the current value
+		relative := strings.HasPrefix(args[2], "+") || strings.HasPrefix(args[2], "-")
+		switch args[0] {
+		case "layout":
+			err = windowmanager.SetGroupLayout(id, args[2])
+		case "master-count":
+			count, e := strconv.Atoi(args[2])
+			if e != nil {
+				return "Invalid master count"
+			}
+			err = windowmanager.SetGroupMasterCount(id, count, relative)
+		case "master-ratio":
+			ratio, e := strconv.ParseFloat(args[2], 64)
+			if e != nil {
+				return "Invalid master ratio"
+			}
+			err = windowmanager.SetGroupMasterRatio(id, ratio, relative)
+		default:
+			panic("Unreachable")
+		}
+		if err != nil {
+			return err.Error()
+		}
 	case "names":
 		if len(args) < 2 {
 			return "No names provided"
@@ -411,6 +740,165 @@ func groupCommand(args []string) string {
 	return ""
 }
 
+func queryCommand(args []string) string {
+	if len(args) == 0 {
+		return "Nothing to query"
+	}
+	var result interface{}
+	switch args[0] {
+	case "windows":
+		result = windowmanager.QueryWindows()
+	case "window":
+		f := flag.NewFlagSet("query", flag.ContinueOnError)
+		id := f.Int("id", 0, "")
+		if err := f.Parse(args[1:]); err != nil {
+			return fmt.Sprintf("Error parsing arguments: %s", err)
+		}
+		info, err := windowmanager.QueryWindow(*id)
+		if err != nil {
+			return err.Error()
+		}
+		result = info
+	case "groups":
+		result = windowmanager.QueryGroups()
+	case "heads":
+		result = windowmanager.QueryHeads()
+	default:
+		return "Unsupported query argument"
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Sprintf("Cannot encode query result: %s", err)
+	}
+	return string(out)
+}
+
+func ruleCommand(args []string) string {
+	if len(args) == 0 {
+		return "No arguments for rule command"
+	}
+	switch args[0] {
+	case "add":
+		r, err := rules.Parse(args[1:])
+		if err != nil {
+			return fmt.Sprintf("Invalid rule: %s", err)
+		}
+		if applyingConfigFile {
+			rules.AddFromConfigFile(r)
+		} else {
+			rules.Add(r)
+		}
+	case "remove":
+		if len(args) < 2 {
+			return "No rule index provided"
+		}
+		index, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "Invalid rule index"
+		}
+		if err := rules.Remove(index); err != nil {
+			return err.Error()
+		}
+	case "clear":
+		rules.Clear()
+	case "list":
+		var r strings.Builder
+		for i, rule := range rules.List() {
+			if i > 0 {
+				r.WriteByte('\n')
+			}
+			r.WriteString(fmt.Sprintf("%d: %s", i, rule))
+		}
+		return r.String()
+	default:
+		return "Unsupported rule argument"
+	}
+	return ""
+}
+
+func sessionCommand(args []string) string {
+	if len(args) == 0 {
+		return "No arguments for session command"
+	}
+	switch args[0] {
+	case "save":
+		if err := windowmanager.SaveSession(); err != nil {
+			return fmt.Sprintf("Cannot save session: %s", err)
+		}
+	case "path":
+		path, err := windowmanager.SessionPath()
+		if err != nil {
+			return err.Error()
+		}
+		return path
+	default:
+		return "Unsupported session argument"
+	}
+	return ""
+}
+
+func bindCommand(args []string) string {
+	f := flag.NewFlagSet("bind", flag.ContinueOnError)
+	release := f.Bool("release", false, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+
+	if f.NArg() == 0 {
+		return strings.Join(keybindings.List(), "\n")
+	}
+	bind := keybindings.Bind
+	if applyingConfigFile {
+		bind = keybindings.BindFromConfigFile
+	}
+	if err := bind(f.Arg(0), *release, f.Args()[1:]); err != nil {
+		return fmt.Sprintf("Cannot bind %s: %s", f.Arg(0), err)
+	}
+	return ""
+}
+
+func unbindCommand(args []string) string {
+	f := flag.NewFlagSet("unbind", flag.ContinueOnError)
+	release := f.Bool("release", false, "")
+	all := f.Bool("all", false, "")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Sprintf("Error parsing arguments: %s", err)
+	}
+
+	if *all {
+		keybindings.UnbindAll()
+		return ""
+	}
+	if f.NArg() == 0 {
+		return "No keys to unbind"
+	}
+	if err := keybindings.Unbind(f.Arg(0), *release); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// execCommand spawns command without waiting for it to finish
+// Single argument is run by shell, so it can contain pipes, redirections etc.
+func execCommand(args []string) string {
+	var cmd *exec.Cmd
+	switch len(args) {
+	case 0:
+		return "No command to execute"
+	case 1:
+		cmd = exec.Command("/bin/sh", "-c", args[0])
+	default:
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Sprintf("Cannot execute command: %s", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	return ""
+}
+
 func parseBorderConfig(args []string) (int, uint32, uint32, uint32, error) {
 	if len(args) < 4 {
 		return 0, 0, 0, 0, fmt.Errorf("too few arguments for border config")
@@ -435,6 +923,38 @@ func parseBorderConfig(args []string) (int, uint32, uint32, uint32, error) {
 	return s, uint32(n), uint32(ac), uint32(att), nil
 }
 
+func parseBorderStyle(args []string) (decoration.BorderStyle, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no border style provided")
+	}
+	switch args[0] {
+	case "flat":
+		return nil, nil
+	case "gradient":
+		if len(args) < 4 {
+			return nil, fmt.Errorf("too few arguments for gradient border style")
+		}
+		states := []decoration.State{decoration.StateNormal, decoration.StateActive, decoration.StateAttention}
+		endColors := make(map[decoration.State]uint32)
+		for i, state := range states {
+			color, err := hex2int(args[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid color: %s", args[i+1])
+			}
+			endColors[state] = uint32(color)
+		}
+		return decoration.NewGradientBorderStyle(endColors), nil
+	case "image":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("no image provided")
+		}
+		paths := make([]string, 3)
+		copy(paths, args[1:])
+		return decoration.NewImageBorderStyle(paths[0], paths[1], paths[2])
+	}
+	return nil, fmt.Errorf("unsupported border style: %s", args[0])
+}
+
 func hex2int(hexStr string) (uint64, error) {
 	hexStr = strings.Replace(hexStr, "0x", "", 1)
 	hexStr = strings.Replace(hexStr, "#", "", 1)
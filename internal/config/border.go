@@ -59,9 +59,24 @@ func SetAllBorders(size int, colorNormal, colorActive, colorAttention uint32) {
 	SetRightBorder(size, colorNormal, colorActive, colorAttention)
 }
 
+// SetBorderStyle sets style used to paint all borders, nil means borders are filled with color
+func SetBorderStyle(style decoration.BorderStyle) {
+	BorderTop.Style = style
+	BorderBottom.Style = style
+	BorderLeft.Style = style
+	BorderRight.Style = style
+}
+
 func setBorder(border *decoration.BorderConfig, size int, colorNormal, colorActive, colorAttention uint32) {
 	border.Size = size
 	border.ColorNormal = colorNormal
 	border.ColorActive = colorActive
 	border.ColorAttention = colorAttention
 }
+
+// width of invisible area along the borders inside the window, where resizing by mouse can start as well,
+// makes thin borders easier to grab
+var ResizeMargin = 0
+
+// radius of rounded corners of window frames, corners are square when zero
+var CornerRadius = 0
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+var configFile = "swm.conf"
+
+// keys of config file and swmctl commands they are translated to, value of the key is appended as arguments
+var configKeys = map[string][]string{
+	"border":               {"config", "border"},
+	"border-top":           {"config", "border-top"},
+	"border-bottom":        {"config", "border-bottom"},
+	"border-left":          {"config", "border-left"},
+	"border-right":         {"config", "border-right"},
+	"border-style":         {"config", "border-style"},
+	"corner-radius":        {"config", "corner-radius"},
+	"resize-margin":        {"config", "resize-margin"},
+	"font":                 {"config", "font"},
+	"info-bg-color":        {"config", "info-bg-color"},
+	"info-text-color":      {"config", "info-text-color"},
+	"move-drag-shortcut":   {"config", "move-drag-shortcut"},
+	"resize-drag-shortcut": {"config", "resize-drag-shortcut"},
+	"placement":            {"config", "placement"},
+	"snap-distance":        {"config", "snap-distance"},
+	"edge-tiling":          {"config", "edge-tiling"},
+	"focus-model":          {"config", "focus-model"},
+	"autoraise":            {"config", "autoraise"},
+	"autoraise-delay":      {"config", "autoraise-delay"},
+	"title-bar":            {"config", "title-bar"},
+	"title-bar-height":     {"config", "title-bar-height"},
+	"title-bar-text-color": {"config", "title-bar-text-color"},
+	"group-mode":           {"group", "mode"},
+	"group-names":          {"group", "names"},
+	"group-per-head":       {"group", "per-head"},
+	"rule":                 {"rule", "add"},
+	"bind":                 {"bind"},
+	"bind-release":         {"bind", "-release"},
+}
+
+// Directive is a single line of config file, translated to swmctl command
+type Directive struct {
+	Line int
+	Args []string
+}
+
+// Errors collects errors of config file, each of them is prefixed by file and line number
+type Errors []string
+
+func (e Errors) Error() string {
+	return strings.Join(e, "\n")
+}
+
+func (e *Errors) Add(file string, line int, msg string) {
+	*e = append(*e, fmt.Sprintf("%s:%d: %s", file, line, msg))
+}
+
+// FindConfigFile finds config file in the same locations as swmrc, returns empty string if there is none
+func FindConfigFile(customPath string) string {
+	return findFile(customPath, configFile)
+}
+
+// ParseConfigFile parses whole config file and returns its directives, or all errors found in the file
+// Each non-empty line, which is not a comment (starting with #), has format: key = value
+func ParseConfigFile(path string) ([]Directive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var directives []Directive
+	var errs Errors
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		i := strings.IndexByte(text, '=')
+		if i < 0 {
+			errs.Add(path, line, "expected key = value")
+			continue
+		}
+		key := strings.TrimSpace(text[:i])
+		command, ok := configKeys[key]
+		if !ok {
+			errs.Add(path, line, fmt.Sprintf("unknown key: %s", key))
+			continue
+		}
+		value, err := shellwords.Parse(text[i+1:])
+		if err != nil {
+			errs.Add(path, line, fmt.Sprintf("invalid value: %s", err))
+			continue
+		}
+		if len(value) == 0 {
+			errs.Add(path, line, fmt.Sprintf("no value for key: %s", key))
+			continue
+		}
+		args := append(append([]string{}, command...), value...)
+		directives = append(directives, Directive{Line: line, Args: args})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return directives, nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "swm.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return f.Name()
+}
+
+func TestParseConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `# comment
+border = 3 B0BEC5 00BCD4 F44336
+
+  # indented comment
+placement=smart
+bind = Mod4-Return exec "xterm -e top"
+bind-release = Alt_L cycle-win-end
+rule = -class Firefox -g 1
+`)
+	defer os.Remove(path)
+
+	directives, err := ParseConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Directive{
+		{Line: 2, Args: []string{"config", "border", "3", "B0BEC5", "00BCD4", "F44336"}},
+		{Line: 5, Args: []string{"config", "placement", "smart"}},
+		{Line: 6, Args: []string{"bind", "Mod4-Return", "exec", "xterm -e top"}},
+		{Line: 7, Args: []string{"bind", "-release", "Alt_L", "cycle-win-end"}},
+		{Line: 8, Args: []string{"rule", "add", "-class", "Firefox", "-g", "1"}},
+	}
+	if !reflect.DeepEqual(expected, directives) {
+		t.Errorf("expected %v, got %v", expected, directives)
+	}
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	path := writeConfigFile(t, `border = 3
+no value separator
+unknown-key = 1
+placement =
+font = "unterminated
+`)
+	defer os.Remove(path)
+
+	directives, err := ParseConfigFile(path)
+	if directives != nil {
+		t.Errorf("nothing should be returned when there are errors, got %v", directives)
+	}
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	expected := []string{
+		path + ":2: expected key = value",
+		path + ":3: unknown key: unknown-key",
+		path + ":4: no value for key: placement",
+		path + ":5: invalid value",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range expected {
+		if !strings.HasPrefix(errs[i], e) {
+			t.Errorf("expected error starting with %q, got %q", e, errs[i])
+		}
+	}
+}
+
+func TestParseConfigFileMissing(t *testing.T) {
+	if _, err := ParseConfigFile("/nonexistent/swm.conf"); err == nil {
+		t.Error("missing file should return error")
+	}
+}
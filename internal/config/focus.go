@@ -0,0 +1,22 @@
+package config
+
+const (
+	// window is focused when clicked
+	FocusModelClick = "click"
+	// window is focused when pointer enters it, focus stays when pointer moves to the root window
+	FocusModelSloppy = "sloppy"
+	// window is focused when pointer enters it, focus is removed when pointer moves to the root window
+	FocusModelStrictMouse = "strict-mouse"
+)
+
+var FocusModel = FocusModelClick
+
+// whether window focused by pointer is raised after AutoRaiseDelay
+var AutoRaise = false
+
+// delay in milliseconds before window focused by pointer is raised
+var AutoRaiseDelay = 300
+
+func IsValidFocusModel(m string) bool {
+	return m == FocusModelClick || m == FocusModelSloppy || m == FocusModelStrictMouse
+}
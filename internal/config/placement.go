@@ -0,0 +1,26 @@
+package config
+
+const (
+	// window is centered on the head under the pointer
+	PlacementPointer = "pointer"
+	// window is centered on the head of focused window
+	PlacementFocused = "focused"
+	// windows are placed diagonally from the top left corner of the head under the pointer
+	PlacementCascade = "cascade"
+	// window is placed where it covers the least area of other windows
+	PlacementSmart = "smart"
+	// window is centered on the pointer, but kept inside the head
+	PlacementUnderMouse = "under-mouse"
+	// transient window or dialog is centered on its parent, other windows are placed like with PlacementPointer
+	PlacementParent = "center-on-parent"
+)
+
+var Placement = PlacementPointer
+
+func IsValidPlacement(p string) bool {
+	switch p {
+	case PlacementPointer, PlacementFocused, PlacementCascade, PlacementSmart, PlacementUnderMouse, PlacementParent:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package config
+
+// distance in pixels, from which dragged window snaps to edges of heads, struts and other windows, 0 disables snapping
+var SnapDistance = 0
+
+// whether window dragged to edge or corner of a head is tiled to its half or quarter when dropped
+var EdgeTiling = false
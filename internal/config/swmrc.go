@@ -14,14 +14,22 @@ var (
 )
 
 // FindAndRunSwmrc finds first existing swmrc file and executes it
-// Searched locations:
-//     - if customPath is provided, only customPath is tried
-//     1) {XDG_CONFIG_HOME}/{swmDir}/{swmrcFile}
-//     2) {HOME}/.config/{swmDir}/{swmrcFile}
-//     3) {HOME}/.{swmDir}/{swmrcFile}
+// Searched locations are described at findFile
 func FindAndRunSwmrc(customPath string) {
 	log.Printf("Trying to execute config")
 
+	if file := findFile(customPath, swmrcFile); file != "" {
+		executeConfig(file)
+	}
+}
+
+// findFile finds first existing file with given name in config locations
+// Searched locations:
+//     - if customPath is provided, only customPath is tried
+//     1) {XDG_CONFIG_HOME}/{swmDir}/{name}
+//     2) {HOME}/.config/{swmDir}/{name}
+//     3) {HOME}/.{swmDir}/{name}
+func findFile(customPath, name string) string {
 	if customPath != "" {
 		path := customPath
 		if path[0] != '/' {
@@ -33,10 +41,9 @@ func FindAndRunSwmrc(customPath string) {
 		}
 		if _, err := os.Stat(path); err != nil {
 			log.Printf("Provided config file does not seem to exist: %s", err)
-		} else {
-			executeConfig(path)
+			return ""
 		}
-		return
+		return path
 	}
 
 	configDir := os.Getenv("XDG_CONFIG_HOME")
@@ -46,28 +53,28 @@ func FindAndRunSwmrc(customPath string) {
 	if configDir != "" {
 		files = append(
 			files,
-			filepath.Join(configDir, swmDir, swmrcFile),
+			filepath.Join(configDir, swmDir, name),
 		)
 	}
 	if homeDir != "" {
 		files = append(
 			files,
-			filepath.Join(homeDir, ".config", swmDir, swmrcFile),
-			filepath.Join(homeDir, fmt.Sprintf(".%s", swmDir), swmrcFile),
+			filepath.Join(homeDir, ".config", swmDir, name),
+			filepath.Join(homeDir, fmt.Sprintf(".%s", swmDir), name),
 		)
 	}
 
 	for _, file := range files {
 		if _, err := os.Stat(file); err == nil {
-			executeConfig(file)
-			return
+			return file
 		}
 	}
 
-	log.Printf("No config file found, searched locations:")
+	log.Printf("No %s file found, searched locations:", name)
 	for _, file := range files {
 		log.Printf("\t%s", file)
 	}
+	return ""
 }
 
 func executeConfig(file string) {
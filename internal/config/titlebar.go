@@ -0,0 +1,7 @@
+package config
+
+// whether new windows get title bar, can be overridden by rules or for each window separately
+var TitleBar = false
+
+var TitleBarHeight = 20
+var TitleBarTextColor uint32 = 0xFFFFFF
@@ -15,10 +15,21 @@ const (
 	Bottom
 )
 
+type State int
+
+const (
+	StateNormal State = iota
+	StateActive
+	StateAttention
+)
+
 type Border struct {
 	position Position
 	win      *xwindow.Window
 	config   *BorderConfig
+	state    State
+	width    int
+	height   int
 }
 
 type BorderConfig struct {
@@ -26,6 +37,13 @@ type BorderConfig struct {
 	ColorNormal    uint32
 	ColorActive    uint32
 	ColorAttention uint32
+	// border is filled with the color of its state when there is no style
+	Style BorderStyle
+}
+
+// BorderStyle paints border window, e.g. with gradient or image
+type BorderStyle interface {
+	Paint(win *xwindow.Window, position Position, width, height int, state State, color uint32)
 }
 
 func CreateBorder(parent *xwindow.Window, position Position, config *BorderConfig) Decoration {
@@ -77,6 +95,13 @@ func (b *Border) ApplyRect(config *WinConfig, rect xrect.Rect, f int) xrect.Rect
 	b.win.MROpt(f, x, y, w, h)
 	b.win.Map()
 
+	if w != b.width || h != b.height {
+		b.width, b.height = w, h
+		if b.config.Style != nil {
+			b.paint()
+		}
+	}
+
 	switch b.position {
 	case Left:
 		newRect.XSet(newRect.X() + b.config.Size)
@@ -118,18 +143,40 @@ func (b *Border) Bottom(config *WinConfig) int {
 }
 
 func (b *Border) Active() {
-	b.win.Change(xproto.CwBackPixel, b.config.ColorActive)
-	b.win.ClearAll()
+	b.setState(StateActive)
 }
 
 func (b *Border) InActive() {
-	b.win.Change(xproto.CwBackPixel, b.config.ColorNormal)
-	b.win.ClearAll()
+	b.setState(StateNormal)
 }
 
 func (b *Border) Attention() {
-	b.win.Change(xproto.CwBackPixel, b.config.ColorAttention)
-	b.win.ClearAll()
+	b.setState(StateAttention)
+}
+
+func (b *Border) setState(state State) {
+	b.state = state
+	b.paint()
+}
+
+func (b *Border) paint() {
+	color := b.color()
+	if b.config.Style == nil || b.width <= 0 || b.height <= 0 {
+		b.win.Change(xproto.CwBackPixel, color)
+		b.win.ClearAll()
+		return
+	}
+	b.config.Style.Paint(b.win, b.position, b.width, b.height, b.state, color)
+}
+
+func (b *Border) color() uint32 {
+	switch b.state {
+	case StateActive:
+		return b.config.ColorActive
+	case StateAttention:
+		return b.config.ColorAttention
+	}
+	return b.config.ColorNormal
 }
 
 func (b *Border) Destroy() {
@@ -77,6 +77,15 @@ func (d *Decorations) Bottom(config *WinConfig) int {
 	return sum
 }
 
+func (d *Decorations) Remove(decoration Decoration) {
+	for i, dec := range *d {
+		if dec == decoration {
+			*d = append((*d)[:i], (*d)[i+1:]...)
+			return
+		}
+	}
+}
+
 func (d *Decorations) Active() {
 	d.forAll(Decoration.Active)
 }
@@ -0,0 +1,180 @@
+package decoration
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"os"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/xgraphics"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+// GradientBorderStyle paints border with gradient from its state color at the outer edge
+// to the end color of the state at the inner edge
+// Gradient changes only across the border, so one pixel long strip is rendered for each border size
+// and X server tiles it over the whole border, resizing the window doesn't render it again
+type GradientBorderStyle struct {
+	endColors map[State]uint32
+	strips    map[gradientStrip]*xgraphics.Image
+}
+
+type gradientStrip struct {
+	position Position
+	size     int
+	state    State
+	color    uint32
+}
+
+func NewGradientBorderStyle(endColors map[State]uint32) *GradientBorderStyle {
+	return &GradientBorderStyle{
+		endColors: endColors,
+		strips:    make(map[gradientStrip]*xgraphics.Image),
+	}
+}
+
+func (s *GradientBorderStyle) Paint(win *xwindow.Window, position Position, width, height int, state State, color uint32) {
+	size := width
+	if position == Top || position == Bottom {
+		size = height
+	}
+	key := gradientStrip{position, size, state, color}
+	ximg, ok := s.strips[key]
+	if !ok {
+		ximg = s.render(win, key)
+		s.strips[key] = ximg
+	}
+	if err := setBackground(win, ximg); err != nil {
+		log.Printf("Cannot paint border: %s", err)
+	}
+}
+
+func (s *GradientBorderStyle) render(win *xwindow.Window, strip gradientStrip) *xgraphics.Image {
+	width, height := strip.size, 1
+	if strip.position == Top || strip.position == Bottom {
+		width, height = 1, strip.size
+	}
+	start, end := bgra(strip.color), bgra(s.endColors[strip.state])
+	ximg := xgraphics.New(win.X, image.Rect(0, 0, width, height))
+	ximg.For(func(x, y int) xgraphics.BGRA {
+		// distance from the outer edge, relative to border size
+		var t float64
+		switch strip.position {
+		case Left:
+			t = ratio(x, width)
+		case Right:
+			t = ratio(width-1-x, width)
+		case Top:
+			t = ratio(y, height)
+		case Bottom:
+			t = ratio(height-1-y, height)
+		}
+		return blend(start, end, t)
+	})
+	return ximg
+}
+
+// ImageBorderStyle paints border with image of its state tiled over the border
+// Each image is rendered once, X server tiles it over borders of any size
+type ImageBorderStyle struct {
+	images   map[State]image.Image
+	rendered map[State]*xgraphics.Image
+}
+
+// NewImageBorderStyle loads images for normal, active and attention state from files,
+// states without a file use the image of normal state
+func NewImageBorderStyle(normal, active, attention string) (*ImageBorderStyle, error) {
+	s := &ImageBorderStyle{
+		images:   make(map[State]image.Image),
+		rendered: make(map[State]*xgraphics.Image),
+	}
+	paths := map[State]string{
+		StateNormal:    normal,
+		StateActive:    active,
+		StateAttention: attention,
+	}
+	for state, path := range paths {
+		if path == "" {
+			path = normal
+		}
+		img, err := loadImage(path)
+		if err != nil {
+			return nil, err
+		}
+		s.images[state] = img
+	}
+	return s, nil
+}
+
+func (s *ImageBorderStyle) Paint(win *xwindow.Window, _ Position, _, _ int, state State, _ uint32) {
+	ximg, ok := s.rendered[state]
+	if !ok {
+		ximg = xgraphics.NewConvert(win.X, s.images[state])
+		s.rendered[state] = ximg
+	}
+	if err := setBackground(win, ximg); err != nil {
+		log.Printf("Cannot paint border: %s", err)
+	}
+}
+
+// setBackground sets image as background pixmap of the window, pixmap is created on the first use
+// and shared by all windows painted with the image
+func setBackground(win *xwindow.Window, ximg *xgraphics.Image) error {
+	if ximg.Pixmap == 0 {
+		if err := ximg.XSurfaceSet(win.Id); err != nil {
+			return err
+		}
+		ximg.XDraw()
+	}
+	win.Change(xproto.CwBackPixmap, uint32(ximg.Pixmap))
+	win.ClearAll()
+	return nil
+}
+
+func loadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %s", path, err)
+	}
+	if img.Bounds().Empty() {
+		return nil, fmt.Errorf("image %s is empty", path)
+	}
+	return img, nil
+}
+
+func bgra(color uint32) xgraphics.BGRA {
+	return xgraphics.BGRA{
+		B: uint8(color & 0xFF),
+		G: uint8((color >> 8) & 0xFF),
+		R: uint8((color >> 16) & 0xFF),
+		A: 0xFF,
+	}
+}
+
+func ratio(pos, size int) float64 {
+	if size <= 1 {
+		return 0
+	}
+	return float64(pos) / float64(size-1)
+}
+
+func blend(from, to xgraphics.BGRA, t float64) xgraphics.BGRA {
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	return xgraphics.BGRA{
+		B: mix(from.B, to.B),
+		G: mix(from.G, to.G),
+		R: mix(from.R, to.R),
+		A: 0xFF,
+	}
+}
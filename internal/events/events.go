@@ -0,0 +1,188 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/util"
+)
+
+const (
+	WindowManaged   = "window-managed"
+	WindowUnmanaged = "window-unmanaged"
+	FocusChanged    = "focus-changed"
+	GroupsChanged   = "groups-changed"
+	StateAdded      = "state-added"
+	StateRemoved    = "state-removed"
+	GeometryChanged = "geometry-changed"
+	HeadsChanged    = "heads-changed"
+
+	// number of events which can wait for subscriber before it is considered too slow and dropped
+	bufferSize = 256
+)
+
+var All = []string{
+	WindowManaged,
+	WindowUnmanaged,
+	FocusChanged,
+	GroupsChanged,
+	StateAdded,
+	StateRemoved,
+	GeometryChanged,
+	HeadsChanged,
+}
+
+type Event struct {
+	Type   string        `json:"event"`
+	Window xproto.Window `json:"window,omitempty"`
+	Data   interface{}   `json:"data,omitempty"`
+}
+
+type StateData struct {
+	State string `json:"state"`
+}
+
+type GroupsData struct {
+	Visible []uint `json:"visible"`
+	Current uint   `json:"current"`
+}
+
+type Subscriber struct {
+	events chan *Event
+	filter util.StringSet
+	// whether events were dropped because the buffer was full
+	overflowed bool
+}
+
+var (
+	// subscribers are added from socket goroutines, so access has to be synchronized
+	mutex       sync.Mutex
+	subscribers = map[*Subscriber]bool{}
+)
+
+// Subscribe creates new subscriber receiving events with given names, or all events if no names are given
+func Subscribe(names []string) (*Subscriber, error) {
+	if len(names) == 0 {
+		names = All
+	}
+	filter := make(util.StringSet)
+	for _, name := range names {
+		if !isValid(name) {
+			return nil, fmt.Errorf("unknown event: %s", name)
+		}
+		filter[name] = true
+	}
+
+	s := &Subscriber{
+		events: make(chan *Event, bufferSize),
+		filter: filter,
+	}
+
+	mutex.Lock()
+	subscribers[s] = true
+	mutex.Unlock()
+
+	return s, nil
+}
+
+func Unsubscribe(s *Subscriber) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	remove(s)
+}
+
+// Events returns channel of events for the subscriber
+// Channel is closed when subscriber doesn't keep up with incoming events or is unsubscribed
+func (s *Subscriber) Events() <-chan *Event {
+	return s.events
+}
+
+// Overflowed returns whether the subscriber was removed because its buffer was full and events were dropped
+func (s *Subscriber) Overflowed() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return s.overflowed
+}
+
+// Wanted returns whether there is anybody subscribed to given event type
+// Useful when the event data are expensive to get
+func Wanted(eventType string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for s := range subscribers {
+		if s.filter[eventType] {
+			return true
+		}
+	}
+	return false
+}
+
+func WindowManagedEvent(win xproto.Window) {
+	publish(&Event{Type: WindowManaged, Window: win})
+}
+
+func WindowUnmanagedEvent(win xproto.Window) {
+	publish(&Event{Type: WindowUnmanaged, Window: win})
+}
+
+func FocusChangedEvent(win xproto.Window) {
+	publish(&Event{Type: FocusChanged, Window: win})
+}
+
+func GroupsChangedEvent(visible []uint, current uint) {
+	publish(&Event{Type: GroupsChanged, Data: &GroupsData{Visible: visible, Current: current}})
+}
+
+func StateAddedEvent(win xproto.Window, state string) {
+	publish(&Event{Type: StateAdded, Window: win, Data: &StateData{State: state}})
+}
+
+func StateRemovedEvent(win xproto.Window, state string) {
+	publish(&Event{Type: StateRemoved, Window: win, Data: &StateData{State: state}})
+}
+
+func GeometryChangedEvent(win xproto.Window, geom xrect.Rect) {
+	g := util.NewGeometry(geom)
+	publish(&Event{Type: GeometryChanged, Window: win, Data: &g})
+}
+
+func HeadsChangedEvent() {
+	publish(&Event{Type: HeadsChanged})
+}
+
+// publish sends event to all interested subscribers without blocking
+// Subscribers which have their buffer full are dropped, so a slow subscriber cannot block the wm
+func publish(e *Event) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for s := range subscribers {
+		if !s.filter[e.Type] {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+			s.overflowed = true
+			remove(s)
+		}
+	}
+}
+
+// remove deletes subscriber and closes its channel, mutex must be held
+func remove(s *Subscriber) {
+	if subscribers[s] {
+		delete(subscribers, s)
+		close(s.events)
+	}
+}
+
+func isValid(name string) bool {
+	for _, n := range All {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
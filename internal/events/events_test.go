@@ -0,0 +1,57 @@
+package events
+
+import "testing"
+
+func TestOverflowRemovesSubscriber(t *testing.T) {
+	s, err := Subscribe([]string{FocusChanged})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= bufferSize; i++ {
+		FocusChangedEvent(1)
+	}
+
+	if !s.Overflowed() {
+		t.Error("subscriber should overflow")
+	}
+	if Wanted(FocusChanged) {
+		t.Error("overflowed subscriber should be removed")
+	}
+	count := 0
+	for range s.Events() {
+		count++
+	}
+	if count != bufferSize {
+		t.Errorf("expected %d buffered events, got %d", bufferSize, count)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	s, err := Subscribe(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	GroupsChangedEvent([]uint{0}, 0)
+	Unsubscribe(s)
+	// unsubscribing twice is fine
+	Unsubscribe(s)
+
+	if s.Overflowed() {
+		t.Error("unsubscribed subscriber should not overflow")
+	}
+	if Wanted(GroupsChanged) {
+		t.Error("unsubscribed subscriber should be removed")
+	}
+	if _, ok := <-s.Events(); !ok {
+		t.Error("buffered event should be still received")
+	}
+	if _, ok := <-s.Events(); ok {
+		t.Error("channel should be closed")
+	}
+}
+
+func TestSubscribeUnknownEvent(t *testing.T) {
+	if _, err := Subscribe([]string{"window-exploded"}); err == nil {
+		t.Error("unknown event should be refused")
+	}
+}
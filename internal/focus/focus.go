@@ -24,6 +24,11 @@ type FocusableWindow interface {
 var x *xgbutil.XUtil
 var windows []FocusableWindow
 
+// sequence number of request sent after the last change made by swm,
+// enter events generated before it were caused by swm (restacking, unmapping windows...), not by the user
+var enterSequence uint16
+var enterSequenceSet bool
+
 func Initialize(_x *xgbutil.XUtil) {
 	x = _x
 }
@@ -39,6 +44,16 @@ func Current() FocusableWindow {
 	return nil
 }
 
+// GetFocusOrder returns ids of all windows ordered by their last focus, most recently focused first
+func GetFocusOrder() []xproto.Window {
+	ids := make([]xproto.Window, len(windows))
+	last := len(windows) - 1
+	for i := range windows {
+		ids[i] = windows[last-i].Id()
+	}
+	return ids
+}
+
 func InitialAdd(w FocusableWindow) {
 	windows = append([]FocusableWindow{w}, windows...)
 }
@@ -79,10 +94,15 @@ func FocusLastWithPreference(isPreferred func(window xproto.Window) bool) {
 	if w := lastFocused(isPreferred); w != nil {
 		Focus(w)
 	} else {
-		xwindow.New(x, x.Dummy()).Focus()
+		ClearFocus()
 	}
 }
 
+// ClearFocus moves focus to the dummy window, so no client has it
+func ClearFocus() {
+	xwindow.New(x, x.Dummy()).Focus()
+}
+
 func SetFocus(w FocusableWindow) {
 	Remove(w)
 	add(w)
@@ -107,7 +127,32 @@ func add(w FocusableWindow) {
 	windows = append(windows, w)
 }
 
+// AcceptFrameFocus returns whether focus event is about the frame window itself, not about its client,
+// frame holds the focus when its client is unmapped (e.g. shaded window)
+func AcceptFrameFocus(mode, detail byte) bool {
+	return (mode == xproto.NotifyModeNormal || mode == xproto.NotifyModeWhileGrabbed) &&
+		(detail == xproto.NotifyDetailNonlinear || detail == xproto.NotifyDetailAncestor || detail == xproto.NotifyDetailInferior)
+}
+
 func AcceptClientFocus(mode, detail byte) bool {
 	return (mode == xproto.NotifyModeNormal || mode == xproto.NotifyModeWhileGrabbed) &&
 		(detail == xproto.NotifyDetailVirtual || detail == xproto.NotifyDetailNonlinearVirtual)
 }
+
+// IgnoreEnterEvents makes enter events generated by requests sent so far ignored, see AcceptEnter
+func IgnoreEnterEvents() {
+	cookie := xproto.GetInputFocus(x.Conn())
+	if _, err := cookie.Reply(); err == nil {
+		enterSequence = cookie.Sequence
+		enterSequenceSet = true
+	}
+}
+
+// AcceptEnter returns whether enter event is caused by user moving the pointer
+func AcceptEnter(mode byte, sequence uint16) bool {
+	if mode != xproto.NotifyModeNormal {
+		return false
+	}
+	// sequence numbers wrap around
+	return !enterSequenceSet || int16(sequence-enterSequence) >= 0
+}
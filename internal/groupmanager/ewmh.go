@@ -6,6 +6,7 @@ import (
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil/ewmh"
 	"github.com/BurntSushi/xgbutil/xprop"
+	"github.com/janbina/swm/internal/events"
 )
 
 const SwmVisibleGroupsAtom = "_SWM_VISIBLE_GROUPS"
@@ -63,8 +64,15 @@ func setCurrentDesktop() {
 
 func setVisibleGroups() {
 	_ = xprop.ChangeProp32(X, X.RootWin(), SwmVisibleGroupsAtom, "CARDINAL", GetVisibleGroups()...)
+	events.GroupsChangedEvent(GetVisibleGroups(), uint(currentGroup))
 }
 
+// setWinDesktop publishes groups of the window, sticky window is on all desktops according to EWMH,
+// so its other groups are kept only internally to be restored when it is unstuck
 func setWinDesktop(win xproto.Window) {
-	_ = xprop.ChangeProp32(X, win, "_NET_WM_DESKTOP", "CARDINAL", GetWinGroups(win)...)
+	if IsWinSticky(win) {
+		_ = xprop.ChangeProp32(X, win, "_NET_WM_DESKTOP", "CARDINAL", StickyGroupID)
+	} else {
+		_ = xprop.ChangeProp32(X, win, "_NET_WM_DESKTOP", "CARDINAL", GetWinGroups(win)...)
+	}
 }
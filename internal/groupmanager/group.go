@@ -4,12 +4,18 @@ import (
 	"time"
 
 	"github.com/BurntSushi/xgb/xproto"
+	"github.com/janbina/swm/internal/layout"
 )
 
 type group struct {
 	name           string
 	shownTimestamp int64
 	windows        map[xproto.Window]bool
+	layout         string
+	masterCount    int
+	masterRatio    float64
+	// id of head the group was last shown on in per-head mode
+	head string
 }
 
 func createGroup(name string) *group {
@@ -17,6 +23,9 @@ func createGroup(name string) *group {
 		name:           name,
 		shownTimestamp: 0,
 		windows:        map[xproto.Window]bool{},
+		layout:         layout.Floating,
+		masterCount:    layout.DefaultMasterCount,
+		masterRatio:    layout.DefaultMasterRatio,
 	}
 }
 
@@ -7,12 +7,15 @@ import (
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/janbina/swm/internal/layout"
 )
 
 type Changes struct {
 	Invisible []xproto.Window
 	Visible   []xproto.Window
 	Raise     []xproto.Window
+	// heads windows have to be moved to, in per-head mode
+	Moves map[xproto.Window]string
 }
 
 type Mode int
@@ -20,7 +23,7 @@ type Mode int
 const (
 	// Id of group which is always visible
 	// Taken from ewmh desktop specification: "0xFFFFFFFF indicates that the window should appear on all groups"
-	stickyGroupID = 0xFFFFFFFF
+	StickyGroupID = 0xFFFFFFFF
 
 	// Mode for initial window group:
 	// * sticky - all windows are initially in group id 0xFFFFFFFF, which is always visible
@@ -50,7 +53,7 @@ func Initialize(x *xgbutil.XUtil) {
 
 	stickyGroup = createGroup("sticky")
 	winToGroups = map[xproto.Window]map[int]bool{}
-	currentGroup = stickyGroupID
+	currentGroup = StickyGroupID
 	GroupMode = ModeAuto
 	setDesktops()
 	setCurrentDesktop()
@@ -80,7 +83,7 @@ func GetCurrentGroup() int {
 }
 
 func IsGroupVisible(group int) bool {
-	if group == stickyGroupID {
+	if group == StickyGroupID {
 		return true
 	}
 	return group >= 0 && group < len(groups) && getGroup(group).isVisible()
@@ -110,7 +113,7 @@ func GetWinGroupNames(win xproto.Window) []string {
 	groups := GetWinGroups(win)
 	names := make([]string, len(groups))
 	for i, g := range groups {
-		if g == stickyGroupID {
+		if g == StickyGroupID {
 			names[i] = "S"
 		} else {
 			names[i] = getGroup(int(g)).name
@@ -122,6 +125,83 @@ func GetWinGroupNames(win xproto.Window) []string {
 	return names
 }
 
+func GetGroupName(group int) string {
+	if group != StickyGroupID && (group < 0 || group >= len(groups)) {
+		return ""
+	}
+	return getGroup(group).name
+}
+
+// GetGroupWindows returns windows which are members of the group, sorted by their id
+func GetGroupWindows(group int) []xproto.Window {
+	if group != StickyGroupID && (group < 0 || group >= len(groups)) {
+		return nil
+	}
+	wins := make([]xproto.Window, 0, len(getGroup(group).windows))
+	for win := range getGroup(group).windows {
+		wins = append(wins, win)
+	}
+	sort.Slice(wins, func(i, j int) bool {
+		return wins[i] < wins[j]
+	})
+	return wins
+}
+
+// GetGroupLayout returns layout of the group with its master count and ratio
+func GetGroupLayout(group int) (string, int, float64) {
+	if group < 0 || group >= len(groups) {
+		return layout.Floating, layout.DefaultMasterCount, layout.DefaultMasterRatio
+	}
+	g := getGroup(group)
+	return g.layout, g.masterCount, g.masterRatio
+}
+
+func SetGroupLayout(group int, l string) error {
+	if group < 0 || group == StickyGroupID {
+		return fmt.Errorf("layout cannot be set for sticky group")
+	}
+	if !layout.IsValid(l) {
+		return fmt.Errorf("unsupported layout: %s", l)
+	}
+	ensureEnoughGroups(group)
+	getGroup(group).layout = l
+	return nil
+}
+
+// SetGroupMasterCount sets number of master windows, if relative is true, count is added to the current value
+func SetGroupMasterCount(group int, count int, relative bool) error {
+	if group < 0 || group == StickyGroupID {
+		return fmt.Errorf("master count cannot be set for sticky group")
+	}
+	ensureEnoughGroups(group)
+	g := getGroup(group)
+	if relative {
+		count += g.masterCount
+	}
+	if count < 0 {
+		count = 0
+	}
+	g.masterCount = count
+	return nil
+}
+
+// SetGroupMasterRatio sets width ratio of master column, if relative is true, ratio is added to the current value
+func SetGroupMasterRatio(group int, ratio float64, relative bool) error {
+	if group < 0 || group == StickyGroupID {
+		return fmt.Errorf("master ratio cannot be set for sticky group")
+	}
+	ensureEnoughGroups(group)
+	g := getGroup(group)
+	if relative {
+		ratio += g.masterRatio
+	}
+	if ratio < 0.05 || ratio > 0.95 {
+		return fmt.Errorf("master ratio has to be between 0.05 and 0.95")
+	}
+	g.masterRatio = ratio
+	return nil
+}
+
 func IsWinInGroup(win xproto.Window, group int) bool {
 	return winToGroups[win][group]
 }
@@ -181,11 +261,15 @@ func ToggleGroupVisibility(group int) *Changes {
 
 func ShowGroupOnly(group int) *Changes {
 	if group < 0 {
-		group = stickyGroupID
+		group = StickyGroupID
 	}
 
 	ensureEnoughGroups(group)
 
+	if PerHead && group != StickyGroupID {
+		return showGroupOnHead(group, getFocusedHead(), true)
+	}
+
 	for i, g := range groups {
 		if i != group {
 			g.makeInvisible()
@@ -200,10 +284,13 @@ func ShowGroupOnly(group int) *Changes {
 }
 
 func ShowGroup(group int) *Changes {
-	if group < 0 || group == stickyGroupID {
+	if group < 0 || group == StickyGroupID {
 		return nil
 	}
 	ensureEnoughGroups(group)
+	if PerHead {
+		return showGroupOnHead(group, getFocusedHead(), false)
+	}
 	getGroup(group).makeVisible()
 
 	updateCurrentGroup()
@@ -214,7 +301,7 @@ func ShowGroup(group int) *Changes {
 }
 
 func HideGroup(group int) *Changes {
-	if group < 0 || group == stickyGroupID {
+	if group < 0 || group == StickyGroupID {
 		return nil
 	}
 	ensureEnoughGroups(group)
@@ -228,7 +315,7 @@ func HideGroup(group int) *Changes {
 
 func SetGroupForWindow(win xproto.Window, group int) *Changes {
 	if group < 0 {
-		group = stickyGroupID
+		group = StickyGroupID
 	}
 
 	ensureEnoughGroups(group)
@@ -245,7 +332,7 @@ func SetGroupForWindow(win xproto.Window, group int) *Changes {
 
 func AddWindowToGroup(win xproto.Window, group int) *Changes {
 	if group < 0 {
-		group = stickyGroupID
+		group = StickyGroupID
 	}
 
 	ensureEnoughGroups(group)
@@ -258,9 +345,9 @@ func AddWindowToGroup(win xproto.Window, group int) *Changes {
 
 func RemoveWindowFromGroup(win xproto.Window, group int) *Changes {
 	if group < 0 {
-		group = stickyGroupID
+		group = StickyGroupID
 	}
-	if group != stickyGroupID && group >= len(groups) {
+	if group != StickyGroupID && group >= len(groups) {
 		return nil
 	}
 
@@ -279,6 +366,31 @@ func RemoveWindowFromGroup(win xproto.Window, group int) *Changes {
 	return createChanges()
 }
 
+// StickWindow adds window to the sticky group, so it is visible regardless of visible groups
+func StickWindow(win xproto.Window) *Changes {
+	return AddWindowToGroup(win, StickyGroupID)
+}
+
+// UnstickWindow removes window from the sticky group,
+// window which is not in any other group is moved to the current group
+func UnstickWindow(win xproto.Window) *Changes {
+	if !IsWinInGroup(win, StickyGroupID) {
+		return nil
+	}
+	if len(winToGroups[win]) > 1 {
+		return RemoveWindowFromGroup(win, StickyGroupID)
+	}
+	group := currentGroup
+	if group == StickyGroupID {
+		group = 0
+	}
+	return SetGroupForWindow(win, group)
+}
+
+func IsWinSticky(win xproto.Window) bool {
+	return IsWinInGroup(win, StickyGroupID)
+}
+
 func GetVisibleGroups() []uint {
 	ids := make([]uint, 0)
 	for i, group := range groups {
@@ -291,7 +403,7 @@ func GetVisibleGroups() []uint {
 
 func getInitialGroupForWindow(win xproto.Window) int {
 	if GroupMode == ModeSticky {
-		return stickyGroupID
+		return StickyGroupID
 	}
 	g, err := ewmh.WmDesktopGet(X, win)
 	if err != nil {
@@ -315,15 +427,17 @@ func moveWinsToGroup(from, to int) {
 }
 
 func ensureEnoughGroups(group int) {
-	if group == stickyGroupID || group < len(groups) {
+	if group == StickyGroupID || group < len(groups) {
 		return
 	}
 	// we can safely ignore changes, cause we are adding new groups, so there are none
 	_ = SetNumberOfGroups(group + 1)
 }
 
+// updateCurrentGroup sets current group to the one which was shown last,
+// groups on the focused head are preferred in per-head mode
 func updateCurrentGroup() {
-	group := stickyGroupID
+	group := StickyGroupID
 	max := int64(0)
 	for i, g := range groups {
 		if g.shownTimestamp > max {
@@ -331,12 +445,22 @@ func updateCurrentGroup() {
 			group = i
 		}
 	}
+	if PerHead {
+		head := getFocusedHead()
+		max = 0
+		for i, g := range groups {
+			if g.shownTimestamp > max && groupHead(g) == head {
+				max = g.shownTimestamp
+				group = i
+			}
+		}
+	}
 	currentGroup = group
 	setCurrentDesktop()
 }
 
 func getGroup(id int) *group {
-	if id == stickyGroupID {
+	if id == StickyGroupID {
 		return stickyGroup
 	}
 	return groups[id]
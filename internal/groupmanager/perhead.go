@@ -0,0 +1,192 @@
+package groupmanager
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/janbina/swm/internal/heads"
+)
+
+var (
+	// PerHead is mode in which each head shows its own groups, showing group on one head doesn't affect the others
+	PerHead bool
+
+	// id of head with the focused window (see heads.Id)
+	focusedHead string
+)
+
+// SetPerHead enables or disables per-head mode, visible groups are assigned to the focused head
+func SetPerHead(enabled bool) *Changes {
+	if PerHead == enabled {
+		return nil
+	}
+	PerHead = enabled
+	if enabled {
+		head := getFocusedHead()
+		for _, g := range groups {
+			if g.isVisible() {
+				g.head = head
+			}
+		}
+	}
+	updateCurrentGroup()
+	setVisibleGroups()
+	return createChanges()
+}
+
+// SetFocusedHead sets head with the focused window, in per-head mode current group is the one visible there
+func SetFocusedHead(head string) {
+	if head == focusedHead {
+		return
+	}
+	focusedHead = head
+	if PerHead {
+		updateCurrentGroup()
+		setVisibleGroups()
+	}
+}
+
+// GetGroupHead returns id of head the group is visible on in per-head mode
+func GetGroupHead(group int) (string, bool) {
+	if !PerHead || group < 0 || group >= len(groups) || !getGroup(group).isVisible() {
+		return "", false
+	}
+	return groupHead(getGroup(group)), true
+}
+
+// GetHeadGroup returns group which was shown last on the head in per-head mode
+func GetHeadGroup(head string) (int, bool) {
+	if !PerHead {
+		return 0, false
+	}
+	head = validHead(head)
+	group, max := 0, int64(0)
+	for i, g := range groups {
+		if g.shownTimestamp > max && groupHead(g) == head {
+			group, max = i, g.shownTimestamp
+		}
+	}
+	return group, max > 0
+}
+
+// GetWinHead returns id of head window belongs to in per-head mode, which is the head of its visible group
+func GetWinHead(win xproto.Window) (string, bool) {
+	if !PerHead || IsWinSticky(win) {
+		return "", false
+	}
+	for _, g := range GetWinGroups(win) {
+		if head, ok := GetGroupHead(int(g)); ok {
+			return head, true
+		}
+	}
+	return "", false
+}
+
+// ShowGroupOnHead shows group on head given by its name or index, if only is true, other groups on the head are hidden
+// Group which is visible on another head swaps its place with groups from the target head
+func ShowGroupOnHead(group int, head string, only bool) (*Changes, error) {
+	if !PerHead {
+		return nil, fmt.Errorf("heads don't have their own groups, per-head mode is disabled")
+	}
+	if group < 0 || group == StickyGroupID {
+		return nil, fmt.Errorf("sticky group is visible on all heads")
+	}
+	i, err := heads.GetHeadIndex(head)
+	if err != nil {
+		return nil, err
+	}
+	return showGroupOnHead(group, heads.Id(i), only), nil
+}
+
+// SwapHeads swaps groups visible on heads given by their names or indexes, windows move with their groups
+func SwapHeads(a, b string) (*Changes, error) {
+	if !PerHead {
+		return nil, fmt.Errorf("heads don't have their own groups, per-head mode is disabled")
+	}
+	i, err := heads.GetHeadIndex(a)
+	if err != nil {
+		return nil, err
+	}
+	j, err := heads.GetHeadIndex(b)
+	if err != nil {
+		return nil, err
+	}
+	a, b = heads.Id(i), heads.Id(j)
+
+	moves := map[xproto.Window]string{}
+	for _, g := range groups {
+		if !g.isVisible() {
+			continue
+		}
+		switch groupHead(g) {
+		case a:
+			moveGroup(g, b, moves)
+		case b:
+			moveGroup(g, a, moves)
+		}
+	}
+
+	updateCurrentGroup()
+	setVisibleGroups()
+
+	changes := createChanges()
+	changes.Moves = moves
+	return changes, nil
+}
+
+func showGroupOnHead(group int, head string, only bool) *Changes {
+	ensureEnoughGroups(group)
+	g := getGroup(group)
+	moves := map[xproto.Window]string{}
+
+	if only {
+		// group visible elsewhere leaves its head to groups from the target head, otherwise they are hidden
+		from := groupHead(g)
+		swap := g.isVisible() && from != head
+		for i, other := range groups {
+			if i == group || !other.isVisible() || groupHead(other) != head {
+				continue
+			}
+			if swap {
+				moveGroup(other, from, moves)
+			} else {
+				other.makeInvisible()
+			}
+		}
+	}
+	moveGroup(g, head, moves)
+	g.makeVisible()
+
+	updateCurrentGroup()
+	setVisibleGroups()
+
+	changes := createChangesWithRaise(group)
+	changes.Moves = moves
+	return changes
+}
+
+// moveGroup assigns group to the head, its windows will be moved there
+func moveGroup(g *group, head string, moves map[xproto.Window]string) {
+	g.head = head
+	for win := range g.windows {
+		if !IsWinSticky(win) {
+			moves[win] = head
+		}
+	}
+}
+
+// groupHead returns head of the group, groups from disconnected heads are shown on the primary one
+func groupHead(g *group) string {
+	return validHead(g.head)
+}
+
+func getFocusedHead() string {
+	return validHead(focusedHead)
+}
+
+func validHead(head string) string {
+	if _, err := heads.GetHeadIndex(head); err == nil && head != "" {
+		return head
+	}
+	return heads.Id(heads.Primary)
+}
@@ -2,6 +2,7 @@ package heads
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/BurntSushi/xgbutil/xinerama"
 	"github.com/BurntSushi/xgbutil/xrect"
@@ -10,6 +11,96 @@ import (
 var Heads xinerama.Heads
 var HeadsStruts xinerama.Heads
 
+// Names contains output names of Heads, name is empty when it is not known
+var Names []string
+
+// Primary is index of primary head in Heads
+var Primary int
+
+type Head struct {
+	Name    string
+	Rect    xrect.Rect
+	Primary bool
+}
+
+// Set replaces current heads, first head is primary when none is marked as such
+func Set(list []Head) {
+	Heads = make(xinerama.Heads, len(list))
+	Names = make([]string, len(list))
+	Primary = 0
+	for i, head := range list {
+		Heads[i] = head.Rect
+		Names[i] = head.Name
+		if head.Primary {
+			Primary = i
+		}
+	}
+}
+
+// GetHeadIndex returns index of head given by its name or index
+func GetHeadIndex(spec string) (int, error) {
+	for i, name := range Names {
+		if name != "" && name == spec {
+			return i, nil
+		}
+	}
+	i, err := strconv.Atoi(spec)
+	if err != nil || i < 0 || i >= len(Heads) {
+		return 0, fmt.Errorf("invalid head: %s", spec)
+	}
+	return i, nil
+}
+
+// Id returns name of head with given index, or the index itself when the name is not known,
+// so it can be used with GetHeadIndex
+func Id(i int) string {
+	if i >= 0 && i < len(Names) && Names[i] != "" {
+		return Names[i]
+	}
+	return strconv.Itoa(i)
+}
+
+// GetTargetHeadIndex returns index of head given by target relative to head with index from,
+// target is next, prev, or name or index of the head
+func GetTargetHeadIndex(target string, from int) (int, error) {
+	n := len(Heads)
+	if n == 0 {
+		return 0, fmt.Errorf("no heads")
+	}
+	switch target {
+	case "next":
+		return (from + 1) % n, nil
+	case "prev":
+		return (from - 1 + n) % n, nil
+	default:
+		return GetHeadIndex(target)
+	}
+}
+
+// GetHeadIndexForRect returns index of head with the largest overlap with rect, or primary head
+func GetHeadIndexForRect(rect xrect.Rect) int {
+	i := xrect.LargestOverlap(rect, Heads)
+	if i < 0 || i >= len(Heads) {
+		i = Primary
+	}
+	return i
+}
+
+// GetHeadIdForRect returns id (see Id) of head with the largest overlap with rect
+func GetHeadIdForRect(rect xrect.Rect) string {
+	return Id(GetHeadIndexForRect(rect))
+}
+
+// GetHeadByName returns head with given name
+func GetHeadByName(name string) (xrect.Rect, bool) {
+	for i, n := range Names {
+		if n != "" && n == name {
+			return Heads[i], true
+		}
+	}
+	return nil, false
+}
+
 func GetHeadForRect(rect xrect.Rect) (xrect.Rect, error) {
 	if len(Heads) == 0 {
 		return nil, fmt.Errorf("no heads")
@@ -32,6 +123,18 @@ func GetHeadForRectStruts(rect xrect.Rect) (xrect.Rect, error) {
 	}
 }
 
+func GetHeadForPointer(x, y int) (xrect.Rect, error) {
+	if len(Heads) == 0 {
+		return nil, fmt.Errorf("no heads")
+	}
+	for _, head := range Heads {
+		if xInRect(x, head) && yInRect(y, head) {
+			return head, nil
+		}
+	}
+	return Heads[0], nil
+}
+
 func GetHeadForPointerStruts(x, y int) (xrect.Rect, error) {
 	if len(HeadsStruts) == 0 {
 		return nil, fmt.Errorf("no heads")
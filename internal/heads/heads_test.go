@@ -0,0 +1,70 @@
+package heads
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+func setTestHeads() {
+	Set([]Head{
+		{Name: "DP-1", Rect: xrect.New(0, 0, 1920, 1080)},
+		{Name: "HDMI-1", Rect: xrect.New(1920, 0, 1280, 1024), Primary: true},
+		{Rect: xrect.New(3200, 0, 800, 600)},
+	})
+}
+
+func TestGetTargetHeadIndex(t *testing.T) {
+	setTestHeads()
+
+	tests := []struct {
+		target   string
+		from     int
+		expected int
+	}{
+		{"next", 0, 1},
+		{"next", 2, 0},
+		{"prev", 0, 2},
+		{"prev", 1, 0},
+		{"HDMI-1", 0, 1},
+		{"2", 0, 2},
+		{"0", 0, 0},
+	}
+	for _, test := range tests {
+		if i, err := GetTargetHeadIndex(test.target, test.from); err != nil || i != test.expected {
+			t.Errorf("%s from %d: expected %d, got %d (%v)", test.target, test.from, test.expected, i, err)
+		}
+	}
+
+	for _, target := range []string{"3", "-1", "VGA-1", ""} {
+		if _, err := GetTargetHeadIndex(target, 0); err == nil {
+			t.Errorf("invalid head %q should return error", target)
+		}
+	}
+}
+
+func TestGetTargetHeadIndexNoHeads(t *testing.T) {
+	Set(nil)
+	if _, err := GetTargetHeadIndex("next", 0); err == nil {
+		t.Error("no heads should return error")
+	}
+}
+
+func TestGetHeadIndexForRect(t *testing.T) {
+	setTestHeads()
+
+	if i := GetHeadIndexForRect(xrect.New(100, 100, 200, 200)); i != 0 {
+		t.Errorf("expected head 0, got %d", i)
+	}
+	// window spanning two heads belongs to the one it overlaps more
+	if i := GetHeadIndexForRect(xrect.New(1800, 100, 400, 200)); i != 1 {
+		t.Errorf("expected head 1, got %d", i)
+	}
+	// window outside of all heads belongs to the primary one
+	if i := GetHeadIndexForRect(xrect.New(5000, 5000, 100, 100)); i != 1 {
+		t.Errorf("expected primary head 1, got %d", i)
+	}
+	if id := GetHeadIdForRect(xrect.New(3300, 100, 100, 100)); id != "2" {
+		t.Errorf("expected head id 2, got %s", id)
+	}
+}
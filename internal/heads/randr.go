@@ -0,0 +1,174 @@
+package heads
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/randr"
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xinerama"
+	"github.com/BurntSushi/xgbutil/xprop"
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// RRGetMonitors request from RandR 1.5, it is not generated in xgb
+const getMonitorsOpcode = 42
+
+var (
+	randrSupported = false
+	// whether RandR supports monitors (version 1.5)
+	randrMonitors = false
+)
+
+// Initialize initializes RandR extension, heads are taken from Xinerama when it is not available
+func Initialize(X *xgbutil.XUtil) {
+	if err := randr.Init(X.Conn()); err != nil {
+		log.Printf("RandR extension is not available: %s", err)
+		return
+	}
+	v, err := randr.QueryVersion(X.Conn(), 1, 5).Reply()
+	if err != nil || v.MajorVersion < 1 || v.MajorVersion == 1 && v.MinorVersion < 2 {
+		log.Printf("RandR 1.2 or newer is required")
+		return
+	}
+	randrSupported = true
+	randrMonitors = v.MajorVersion > 1 || v.MinorVersion >= 5
+}
+
+// SelectChanges subscribes to RandR notifications about outputs and screen changes,
+// onChange is called for each of them
+func SelectChanges(X *xgbutil.XUtil, onChange func()) {
+	if !randrSupported {
+		return
+	}
+	mask := randr.NotifyMaskScreenChange | randr.NotifyMaskCrtcChange | randr.NotifyMaskOutputChange
+	if err := randr.SelectInputChecked(X.Conn(), X.RootWin(), uint16(mask)).Check(); err != nil {
+		log.Printf("Cannot select RandR input: %s", err)
+		return
+	}
+	// xevent doesn't dispatch RandR events, so we have to catch them in a hook
+	xevent.HookFun(func(X *xgbutil.XUtil, event interface{}) bool {
+		switch event.(type) {
+		case randr.ScreenChangeNotifyEvent, randr.NotifyEvent:
+			onChange()
+			return false
+		}
+		return true
+	}).Connect(X)
+}
+
+// Query returns current heads, RandR monitors are preferred, then active RandR outputs and Xinerama heads
+func Query(X *xgbutil.XUtil) ([]Head, error) {
+	if randrMonitors {
+		if h, err := queryMonitors(X); err == nil && len(h) > 0 {
+			return h, nil
+		} else if err != nil {
+			log.Printf("Cannot get RandR monitors: %s", err)
+		}
+	}
+	if randrSupported {
+		if h, err := queryOutputs(X); err == nil && len(h) > 0 {
+			return h, nil
+		} else if err != nil {
+			log.Printf("Cannot get RandR outputs: %s", err)
+		}
+	}
+	rects, err := xinerama.PhysicalHeads(X)
+	if err != nil {
+		return nil, err
+	}
+	h := make([]Head, len(rects))
+	for i, rect := range rects {
+		h[i] = Head{Rect: rect, Primary: i == 0}
+	}
+	return h, nil
+}
+
+func queryMonitors(X *xgbutil.XUtil) ([]Head, error) {
+	c := X.Conn()
+	c.ExtLock.RLock()
+	opcode, ok := c.Extensions["RANDR"]
+	c.ExtLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("RandR is not initialized")
+	}
+
+	buf := make([]byte, 12)
+	buf[0] = opcode
+	buf[1] = getMonitorsOpcode
+	xgb.Put16(buf[2:], 3) // request length in 4-byte units
+	xgb.Put32(buf[4:], uint32(X.RootWin()))
+	buf[8] = 1 // only active monitors
+
+	cookie := c.NewCookie(true, true)
+	c.NewRequest(buf, cookie)
+	reply, err := cookie.Reply()
+	if err != nil {
+		return nil, err
+	}
+	if len(reply) < 32 {
+		return nil, fmt.Errorf("invalid reply length %d", len(reply))
+	}
+
+	n := int(xgb.Get32(reply[12:]))
+	heads := make([]Head, 0, n)
+	b := 32
+	for i := 0; i < n; i++ {
+		if len(reply) < b+24 {
+			return nil, fmt.Errorf("invalid reply length %d", len(reply))
+		}
+		name, _ := xprop.AtomName(X, xproto.Atom(xgb.Get32(reply[b:])))
+		heads = append(heads, Head{
+			Name:    name,
+			Primary: reply[b+4] != 0,
+			Rect: xrect.New(
+				int(int16(xgb.Get16(reply[b+8:]))),
+				int(int16(xgb.Get16(reply[b+10:]))),
+				int(xgb.Get16(reply[b+12:])),
+				int(xgb.Get16(reply[b+14:])),
+			),
+		})
+		outputs := int(xgb.Get16(reply[b+6:]))
+		b += 24 + 4*outputs
+	}
+	return heads, nil
+}
+
+func queryOutputs(X *xgbutil.XUtil) ([]Head, error) {
+	c := X.Conn()
+	res, err := randr.GetScreenResourcesCurrent(c, X.RootWin()).Reply()
+	if err != nil {
+		return nil, err
+	}
+	var primary randr.Output
+	if p, err := randr.GetOutputPrimary(c, X.RootWin()).Reply(); err == nil {
+		primary = p.Output
+	}
+
+	heads := make([]Head, 0, len(res.Outputs))
+	crtcs := make(map[randr.Crtc]bool)
+	for _, output := range res.Outputs {
+		info, err := randr.GetOutputInfo(c, output, res.ConfigTimestamp).Reply()
+		if err != nil || info.Connection != randr.ConnectionConnected || info.Crtc == 0 {
+			continue
+		}
+		// cloned outputs share the crtc, they form a single head
+		if crtcs[info.Crtc] {
+			continue
+		}
+		crtcs[info.Crtc] = true
+		crtc, err := randr.GetCrtcInfo(c, info.Crtc, res.ConfigTimestamp).Reply()
+		if err != nil || crtc.Width == 0 || crtc.Height == 0 {
+			continue
+		}
+		heads = append(heads, Head{
+			Name:    string(info.Name),
+			Primary: output == primary,
+			Rect:    xrect.New(int(crtc.X), int(crtc.Y), int(crtc.Width), int(crtc.Height)),
+		})
+	}
+	return heads, nil
+}
@@ -0,0 +1,341 @@
+package keybindings
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/keybind"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/janbina/swm/internal/util"
+)
+
+// separates keys of a chord, e.g. "Mod4-w ; f"
+const chordSeparator = ";"
+
+type key struct {
+	mods  uint16
+	codes []xproto.Keycode
+}
+
+type binding struct {
+	keys    string
+	chord   []key
+	release bool
+	command []string
+	// whether the binding comes from config file, such bindings are replaced on reload
+	fromConfigFile bool
+}
+
+var (
+	X   *xgbutil.XUtil
+	run func(args []string)
+
+	bindings []*binding
+	// keys grabbed on the root window, first keys of all bindings
+	grabbed []key
+
+	// bindings which can still be completed by following keys of the current chord, nil if there is no chord
+	chordCandidates []*binding
+	chordPos        int
+
+	// modifiers whose release bindings wait for the release, keyboard is grabbed until then
+	armedMods uint16
+)
+
+// Initialize starts listening for key events on the root window
+// Commands of triggered bindings are executed by runner, on the X event loop goroutine
+func Initialize(x *xgbutil.XUtil, runner func(args []string)) {
+	X = x
+	run = runner
+	xevent.KeyPressFun(keyPress).Connect(X, X.RootWin())
+	xevent.KeyReleaseFun(keyRelease).Connect(X, X.RootWin())
+	// keys come to the dummy window while the keyboard is grabbed, handlers are connected only once
+	// and never detached, as the dummy window has other handlers (e.g. selection clear) and is used
+	// for keyboard move/resize mode as well
+	xevent.KeyPressFun(func(X *xgbutil.XUtil, e xevent.KeyPressEvent) {
+		if isGrabbed() {
+			keyPress(X, e)
+		}
+	}).Connect(X, X.Dummy())
+	xevent.KeyReleaseFun(func(X *xgbutil.XUtil, e xevent.KeyReleaseEvent) {
+		if isGrabbed() {
+			keyRelease(X, e)
+		}
+	}).Connect(X, X.Dummy())
+}
+
+// Bind binds command to keys, replacing the previous binding of the same keys
+// Keys are in keybind format (e.g. Mod4-Return), more keys separated by ';' form a chord
+// Release bindings are triggered when the (last) key is released
+func Bind(keys string, release bool, command []string) error {
+	return bind(keys, release, command, false)
+}
+
+// BindFromConfigFile binds command like Bind, the binding is removed by UnbindFromConfigFile
+func BindFromConfigFile(keys string, release bool, command []string) error {
+	return bind(keys, release, command, true)
+}
+
+func bind(keys string, release bool, command []string, fromConfigFile bool) error {
+	if len(command) == 0 {
+		return fmt.Errorf("no command to bind")
+	}
+	chord, err := parseChord(keys)
+	if err != nil {
+		return err
+	}
+	b := &binding{
+		keys:           normalizeKeys(keys),
+		chord:          chord,
+		release:        release,
+		command:        command,
+		fromConfigFile: fromConfigFile,
+	}
+	removeBinding(b.keys, release)
+	bindings = append(bindings, b)
+	regrab()
+	return nil
+}
+
+func Unbind(keys string, release bool) error {
+	if !removeBinding(normalizeKeys(keys), release) {
+		return fmt.Errorf("no binding for %s", keys)
+	}
+	regrab()
+	return nil
+}
+
+// UnbindFromConfigFile removes bindings from config file, bindings added by swmctl are kept
+func UnbindFromConfigFile() {
+	kept := bindings[:0]
+	for _, b := range bindings {
+		if !b.fromConfigFile {
+			kept = append(kept, b)
+		}
+	}
+	bindings = kept
+	regrab()
+}
+
+func UnbindAll() {
+	bindings = nil
+	regrab()
+}
+
+// List returns all bindings in format accepted by swmctl bind
+func List() []string {
+	l := make([]string, len(bindings))
+	for i, b := range bindings {
+		prefix := ""
+		if b.release {
+			prefix = "-release "
+		}
+		l[i] = fmt.Sprintf("%s%q %s", prefix, b.keys, util.JoinArgs(b.command))
+	}
+	return l
+}
+
+func removeBinding(keys string, release bool) bool {
+	for i, b := range bindings {
+		if b.keys == keys && b.release == release {
+			bindings = append(bindings[:i], bindings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func parseChord(keys string) ([]key, error) {
+	parts := strings.Split(keys, chordSeparator)
+	chord := make([]key, len(parts))
+	for i, part := range parts {
+		mods, codes, err := keybind.ParseString(X, strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		chord[i] = key{mods, codes}
+	}
+	return chord, nil
+}
+
+func normalizeKeys(keys string) string {
+	parts := strings.Split(keys, chordSeparator)
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return strings.Join(parts, " "+chordSeparator+" ")
+}
+
+// regrab ungrabs all keys and grabs first keys of all bindings again
+// Release bindings of modifier keys are not grabbed, grabbing modifier would take whole keyboard from clients
+// every time the modifier is pressed, see arm
+func regrab() {
+	root := X.RootWin()
+	for _, k := range grabbed {
+		for _, code := range k.codes {
+			keybind.Ungrab(X, root, k.mods, code)
+		}
+	}
+	grabbed = nil
+	for _, b := range bindings {
+		if b.isModifierRelease() {
+			continue
+		}
+		k := b.chord[0]
+		for _, code := range k.codes {
+			if err := keybind.GrabChecked(X, root, k.mods, code); err != nil {
+				log.Printf("Cannot grab %s: %s", b.keys, err)
+			}
+		}
+		grabbed = append(grabbed, k)
+	}
+}
+
+// isModifierRelease returns whether binding is triggered by release of a single modifier key, e.g. Alt_L
+func (b *binding) isModifierRelease() bool {
+	if !b.release || len(b.chord) != 1 || b.chord[0].mods != 0 {
+		return false
+	}
+	return modifiersOf(b.chord[0]) != 0
+}
+
+// modifiersOf returns modifiers of the key, 0 if it is not a modifier key
+func modifiersOf(k key) uint16 {
+	mods := uint16(0)
+	for _, code := range k.codes {
+		mods |= keybind.ModGet(X, code)
+	}
+	return mods
+}
+
+// arm grabs keyboard after binding with mods was triggered, if there is a release binding of any of the modifiers
+// The grab lasts until the modifier is released, so its release binding is triggered (e.g. Mod1-Tab cycle-win
+// followed by release of Alt_L ends the cycling)
+func arm(mods uint16) {
+	for _, b := range bindings {
+		if b.isModifierRelease() && modifiersOf(b.chord[0])&mods != 0 {
+			armedMods |= modifiersOf(b.chord[0]) & mods
+		}
+	}
+	if armedMods == 0 || chordCandidates != nil {
+		return
+	}
+	if err := keybind.DummyGrab(X); err != nil {
+		log.Printf("Cannot grab keyboard for release binding: %s", err)
+		armedMods = 0
+	}
+}
+
+func disarm() {
+	if armedMods == 0 {
+		return
+	}
+	armedMods = 0
+	if chordCandidates == nil {
+		keybind.DummyUngrab(X)
+	}
+}
+
+// ReleaseGrab forgets current chord and armed release bindings without ungrabbing the keyboard,
+// used when keyboard grab is taken over by another mode (e.g. keyboard move)
+func ReleaseGrab() {
+	chordCandidates = nil
+	chordPos = 0
+	armedMods = 0
+}
+
+func isGrabbed() bool {
+	return chordCandidates != nil || armedMods != 0
+}
+
+func keyPress(X *xgbutil.XUtil, e xevent.KeyPressEvent) {
+	mods, code := keybind.DeduceKeyInfo(e.State, e.Detail)
+	handleKey(mods, code, false)
+}
+
+func keyRelease(X *xgbutil.XUtil, e xevent.KeyReleaseEvent) {
+	mods, code := keybind.DeduceKeyInfo(e.State, e.Detail)
+	// when modifier key itself is released, its modifier is still in the state
+	released := keybind.ModGet(X, code)
+	mods &^= released
+	handleKey(mods, code, true)
+	if released&armedMods != 0 {
+		disarm()
+	}
+}
+
+func handleKey(mods uint16, code xproto.Keycode, release bool) {
+	candidates := chordCandidates
+	if candidates == nil {
+		candidates = bindings
+	}
+
+	var complete *binding
+	next := make([]*binding, 0)
+	for _, b := range candidates {
+		if !b.chord[chordPos].matches(mods, code) {
+			continue
+		}
+		if len(b.chord) == chordPos+1 {
+			if b.release == release {
+				complete = b
+			}
+		} else if !release {
+			next = append(next, b)
+		}
+	}
+
+	switch {
+	case complete != nil:
+		// chord has to end before the command is run, as the command might grab keyboard itself
+		endChord()
+		if !release && mods != 0 {
+			arm(mods)
+		}
+		log.Printf("Running key binding %s: %s", complete.keys, complete.command)
+		run(complete.command)
+	case len(next) > 0:
+		continueChord(next)
+	case chordCandidates != nil && !release && keybind.ModGet(X, code) == 0:
+		// unbound key ends the chord, pressing modifiers alone doesn't
+		endChord()
+	}
+}
+
+// continueChord waits for the next key of a chord, keyboard is grabbed so that all keys come to us
+func continueChord(next []*binding) {
+	if chordCandidates == nil && armedMods == 0 {
+		if err := keybind.DummyGrab(X); err != nil {
+			log.Printf("Cannot grab keyboard for chord: %s", err)
+			return
+		}
+	}
+	chordCandidates = next
+	chordPos++
+}
+
+func endChord() {
+	if chordCandidates == nil {
+		return
+	}
+	if armedMods == 0 {
+		keybind.DummyUngrab(X)
+	}
+	chordCandidates = nil
+	chordPos = 0
+}
+
+func (k key) matches(mods uint16, code xproto.Keycode) bool {
+	if k.mods != mods {
+		return false
+	}
+	for _, c := range k.codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
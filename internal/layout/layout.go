@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+const (
+	// windows are left where they are
+	Floating = "floating"
+	// master windows are stacked in the left column, the rest in the right column
+	TileLeft = "tile-left"
+	// all windows take the whole area
+	Monocle = "monocle"
+	// windows are arranged to grid with (almost) the same number of rows and columns
+	Grid = "grid"
+
+	DefaultMasterCount = 1
+	DefaultMasterRatio = 0.5
+)
+
+func IsValid(layout string) bool {
+	switch layout {
+	case Floating, TileLeft, Monocle, Grid:
+		return true
+	}
+	return false
+}
+
+// Arrange returns geometries for n windows arranged in area according to layout
+// Returns nil for floating layout, as its windows are not arranged
+func Arrange(layout string, area xrect.Rect, n, masterCount int, masterRatio float64) []xrect.Rect {
+	if n == 0 {
+		return nil
+	}
+	switch layout {
+	case TileLeft:
+		return tileLeft(area, n, masterCount, masterRatio)
+	case Monocle:
+		return monocle(area, n)
+	case Grid:
+		return grid(area, n)
+	}
+	return nil
+}
+
+func tileLeft(area xrect.Rect, n, masterCount int, masterRatio float64) []xrect.Rect {
+	if masterCount <= 0 || masterCount >= n {
+		// there is only one column
+		return column(area.X(), area.Y(), area.Width(), area.Height(), n)
+	}
+	masterWidth := int(float64(area.Width()) * masterRatio)
+	rects := column(area.X(), area.Y(), masterWidth, area.Height(), masterCount)
+	return append(rects, column(area.X()+masterWidth, area.Y(), area.Width()-masterWidth, area.Height(), n-masterCount)...)
+}
+
+func monocle(area xrect.Rect, n int) []xrect.Rect {
+	rects := make([]xrect.Rect, n)
+	for i := range rects {
+		rects[i] = xrect.New(area.Pieces())
+	}
+	return rects
+}
+
+func grid(area xrect.Rect, n int) []xrect.Rect {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := int(math.Ceil(float64(n) / float64(cols)))
+
+	rects := make([]xrect.Rect, 0, n)
+	for r := 0; r < rows; r++ {
+		y, h := split(area.Y(), area.Height(), rows, r)
+		// last row takes all remaining windows, which might be less than number of columns
+		inRow := cols
+		if r == rows-1 {
+			inRow = n - r*cols
+		}
+		rects = append(rects, row(area.X(), y, area.Width(), h, inRow)...)
+	}
+	return rects
+}
+
+func column(x, y, width, height, n int) []xrect.Rect {
+	rects := make([]xrect.Rect, n)
+	for i := range rects {
+		cy, ch := split(y, height, n, i)
+		rects[i] = xrect.New(x, cy, width, ch)
+	}
+	return rects
+}
+
+func row(x, y, width, height, n int) []xrect.Rect {
+	rects := make([]xrect.Rect, n)
+	for i := range rects {
+		cx, cw := split(x, width, n, i)
+		rects[i] = xrect.New(cx, y, cw, height)
+	}
+	return rects
+}
+
+// split divides interval starting at start with given size to n parts and returns start and size of i-th part
+// The last part takes the remainder, so parts cover the whole interval
+func split(start, size, n, i int) (int, int) {
+	part := size / n
+	if i == n-1 {
+		return start + i*part, size - i*part
+	}
+	return start + i*part, part
+}
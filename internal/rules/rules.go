@@ -0,0 +1,258 @@
+package rules
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/janbina/swm/internal/util"
+)
+
+const (
+	LayerAbove   = "above"
+	LayerBelow   = "below"
+	LayerDefault = "default"
+)
+
+// Properties of window which rules are matched against
+type Properties struct {
+	Class     string
+	Instance  string
+	Title     string
+	Types     util.StringSet
+	Transient bool
+}
+
+// Actions applied to matching window, nil values are left untouched
+type Actions struct {
+	Group       *int
+	Geometry    *util.RelativeGeometry
+	Layer       *string
+	SkipTaskbar *bool
+	Fullscreen  *bool
+	Maximized   *bool
+	Decorate    *bool
+	TitleBar    *bool
+	FocusOnMap  *bool
+}
+
+type Rule struct {
+	class     string
+	instance  string
+	title     *regexp.Regexp
+	winType   string
+	transient *bool
+
+	actions Actions
+	source  string
+	// whether the rule comes from config file, such rules are replaced on reload
+	fromConfigFile bool
+}
+
+var rules []*Rule
+
+// Parse creates rule from swmctl arguments, matching conditions and actions are specified by flags
+func Parse(args []string) (*Rule, error) {
+	f := flag.NewFlagSet("rule", flag.ContinueOnError)
+	f.SetOutput(ioutil.Discard)
+
+	class := f.String("class", "", "")
+	instance := f.String("instance", "", "")
+	title := f.String("title", "", "")
+	winType := f.String("type", "", "")
+	transient := f.Bool("transient", false, "")
+
+	group := f.Int("group", 0, "")
+	origin := f.String("o", "nw", "")
+	x := f.Int("x", 0, "")
+	y := f.Int("y", 0, "")
+	w := f.Int("w", 0, "")
+	h := f.Int("h", 0, "")
+	xr := f.Float64("xr", 0, "")
+	yr := f.Float64("yr", 0, "")
+	wr := f.Float64("wr", 0, "")
+	hr := f.Float64("hr", 0, "")
+	layer := f.String("layer", "", "")
+	skipTaskbar := f.Bool("skip-taskbar", false, "")
+	fullscreen := f.Bool("fullscreen", false, "")
+	maximized := f.Bool("maximized", false, "")
+	decorate := f.Bool("decorate", true, "")
+	titleBar := f.Bool("title-bar", true, "")
+	focusOnMap := f.Bool("focus", true, "")
+
+	if err := f.Parse(args); err != nil {
+		return nil, err
+	}
+	if f.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument: %s", f.Arg(0))
+	}
+
+	r := &Rule{
+		class:    *class,
+		instance: *instance,
+		winType:  normalizeType(*winType),
+		source:   util.JoinArgs(args),
+	}
+
+	var err error
+	geometry := false
+	f.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "title":
+			re, e := regexp.Compile(*title)
+			if e != nil {
+				err = fmt.Errorf("invalid title regex: %s", e)
+			}
+			r.title = re
+		case "transient":
+			r.transient = transient
+		case "group":
+			r.actions.Group = group
+		case "o", "x", "y", "w", "h", "xr", "yr", "wr", "hr":
+			geometry = true
+		case "layer":
+			if *layer != LayerAbove && *layer != LayerBelow && *layer != LayerDefault {
+				err = fmt.Errorf("invalid layer: %s", *layer)
+			}
+			r.actions.Layer = layer
+		case "skip-taskbar":
+			r.actions.SkipTaskbar = skipTaskbar
+		case "fullscreen":
+			r.actions.Fullscreen = fullscreen
+		case "maximized":
+			r.actions.Maximized = maximized
+		case "decorate":
+			r.actions.Decorate = decorate
+		case "title-bar":
+			r.actions.TitleBar = titleBar
+		case "focus":
+			r.actions.FocusOnMap = focusOnMap
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if geometry {
+		r.actions.Geometry = &util.RelativeGeometry{
+			Origin: *origin,
+			X:      *x, Y: *y, W: *w, H: *h,
+			XR: *xr, YR: *yr, WR: *wr, HR: *hr,
+		}
+	}
+
+	return r, nil
+}
+
+func Add(r *Rule) {
+	rules = append(rules, r)
+}
+
+// AddFromConfigFile adds rule from config file, it is removed by RemoveFromConfigFile
+func AddFromConfigFile(r *Rule) {
+	r.fromConfigFile = true
+	Add(r)
+}
+
+// RemoveFromConfigFile removes rules added from config file, rules added by swmctl are kept
+func RemoveFromConfigFile() {
+	kept := rules[:0]
+	for _, r := range rules {
+		if !r.fromConfigFile {
+			kept = append(kept, r)
+		}
+	}
+	rules = kept
+}
+
+func Remove(index int) error {
+	if index < 0 || index >= len(rules) {
+		return fmt.Errorf("no rule with index %d", index)
+	}
+	rules = append(rules[:index], rules[index+1:]...)
+	return nil
+}
+
+func Clear() {
+	rules = nil
+}
+
+// List returns source of all rules in order they are applied
+func List() []string {
+	l := make([]string, len(rules))
+	for i, r := range rules {
+		l[i] = r.source
+	}
+	return l
+}
+
+// Match returns actions of all rules matching the window merged together,
+// when more rules set the same action, the one added later wins
+func Match(p *Properties) *Actions {
+	a := &Actions{}
+	for _, r := range rules {
+		if r.matches(p) {
+			a.merge(&r.actions)
+		}
+	}
+	return a
+}
+
+func (r *Rule) matches(p *Properties) bool {
+	if r.class != "" && r.class != p.Class {
+		return false
+	}
+	if r.instance != "" && r.instance != p.Instance {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(p.Title) {
+		return false
+	}
+	if r.winType != "" && !p.Types[r.winType] {
+		return false
+	}
+	if r.transient != nil && *r.transient != p.Transient {
+		return false
+	}
+	return true
+}
+
+func (a *Actions) merge(other *Actions) {
+	if other.Group != nil {
+		a.Group = other.Group
+	}
+	if other.Geometry != nil {
+		a.Geometry = other.Geometry
+	}
+	if other.Layer != nil {
+		a.Layer = other.Layer
+	}
+	if other.SkipTaskbar != nil {
+		a.SkipTaskbar = other.SkipTaskbar
+	}
+	if other.Fullscreen != nil {
+		a.Fullscreen = other.Fullscreen
+	}
+	if other.Maximized != nil {
+		a.Maximized = other.Maximized
+	}
+	if other.Decorate != nil {
+		a.Decorate = other.Decorate
+	}
+	if other.TitleBar != nil {
+		a.TitleBar = other.TitleBar
+	}
+	if other.FocusOnMap != nil {
+		a.FocusOnMap = other.FocusOnMap
+	}
+}
+
+// normalizeType allows to specify window type in short form, e.g. dialog instead of _NET_WM_WINDOW_TYPE_DIALOG
+func normalizeType(t string) string {
+	if t == "" || strings.HasPrefix(t, "_NET_WM_WINDOW_TYPE_") {
+		return t
+	}
+	return "_NET_WM_WINDOW_TYPE_" + strings.ToUpper(t)
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/janbina/swm/internal/util"
+)
+
+// Session is wm state of managed windows, which is saved on shutdown and restored when windows are managed again
+type Session struct {
+	Windows []*Window `json:"windows"`
+	// visible groups in order they were shown, the current group is the last one
+	VisibleGroups []uint `json:"visible_groups,omitempty"`
+}
+
+// Window is saved state of single window
+// Window is matched by its id first, as it doesn't change when only the wm is restarted,
+// and by its class, role and command otherwise
+type Window struct {
+	Id       xproto.Window `json:"id"`
+	Class    string        `json:"class"`
+	Instance string        `json:"instance"`
+	Role     string        `json:"role,omitempty"`
+	Command  string        `json:"command,omitempty"`
+
+	Groups   []uint        `json:"groups"`
+	Layer    string        `json:"layer"`
+	Geometry util.Geometry `json:"geometry"`
+	// geometries the window returns to when it leaves maximized, fullscreen or shaded state
+	SavedGeometries map[string]util.Geometry `json:"saved_geometries,omitempty"`
+	// position in focus order, 0 is the most recently focused window
+	Focus int `json:"focus"`
+	// position in stacking order, 0 is the bottom window
+	Stack int `json:"stack"`
+
+	used bool
+}
+
+// Path returns path of session file for given display,
+// which is {XDG_STATE_HOME}/swm/session-{display}.json, or {HOME}/.local/state/swm/... if XDG_STATE_HOME is not set
+func Path(display int) (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir := os.Getenv("HOME")
+		if homeDir == "" {
+			return "", fmt.Errorf("neither XDG_STATE_HOME nor HOME is set")
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+	return filepath.Join(stateDir, "swm", fmt.Sprintf("session-%d.json", display)), nil
+}
+
+func Save(path string, s *Session) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// write to temporary file first, so that crash during saving doesn't destroy previous session
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func Load(path string) (*Session, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %s", path, err)
+	}
+	return s, nil
+}
+
+// HasWindow returns whether there is saved state for window with the same id and class,
+// ids are reused after X server restart, so the id alone doesn't identify the window
+func (s *Session) HasWindow(w *Window) bool {
+	for _, saved := range s.Windows {
+		if saved.Id == w.Id && saved.sameClass(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match finds saved state for window, each saved state is matched at most once
+// Returns nil if there is no matching window
+func (s *Session) Match(w *Window) *Window {
+	if s == nil {
+		return nil
+	}
+	for _, saved := range s.Windows {
+		if !saved.used && saved.Id == w.Id && saved.sameClass(w) {
+			saved.used = true
+			return saved
+		}
+	}
+	for _, saved := range s.Windows {
+		if !saved.used && saved.sameClass(w) && saved.Role == w.Role && saved.Command == w.Command {
+			saved.used = true
+			return saved
+		}
+	}
+	return nil
+}
+
+func (w *Window) sameClass(other *Window) bool {
+	return w.Class == other.Class && w.Instance == other.Instance
+}
@@ -0,0 +1,68 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHasWindowRequiresClass(t *testing.T) {
+	s := &Session{Windows: []*Window{{Id: 10, Class: "XTerm", Instance: "xterm"}}}
+
+	if !s.HasWindow(&Window{Id: 10, Class: "XTerm", Instance: "xterm"}) {
+		t.Error("window with the same id and class should be found")
+	}
+	// ids are reused after X server restart
+	if s.HasWindow(&Window{Id: 10, Class: "Firefox", Instance: "Navigator"}) {
+		t.Error("window with different class should not be found")
+	}
+	if s.HasWindow(&Window{Id: 11, Class: "XTerm", Instance: "xterm"}) {
+		t.Error("window with different id should not be found")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	s := &Session{Windows: []*Window{
+		{Id: 10, Class: "XTerm", Instance: "xterm", Command: "xterm"},
+		{Id: 11, Class: "XTerm", Instance: "xterm", Command: "xterm"},
+	}}
+
+	if m := s.Match(&Window{Id: 11, Class: "XTerm", Instance: "xterm"}); m == nil || m.Id != 11 {
+		t.Errorf("window should be matched by id, got %v", m)
+	}
+	// the same id with different class is matched by class, role and command
+	if m := s.Match(&Window{Id: 11, Class: "XTerm", Instance: "xterm", Command: "xterm"}); m == nil || m.Id != 10 {
+		t.Errorf("window should be matched by class and command, got %v", m)
+	}
+	// each saved window is matched at most once
+	if m := s.Match(&Window{Id: 12, Class: "XTerm", Instance: "xterm", Command: "xterm"}); m != nil {
+		t.Errorf("no window should be matched, got %v", m)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "swm", "session-0.json")
+	s := &Session{
+		Windows:       []*Window{{Id: 10, Class: "XTerm", Groups: []uint{1, 2}}},
+		VisibleGroups: []uint{2, 1},
+	}
+	if err := Save(path, s); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Windows) != 1 || loaded.Windows[0].Id != 10 || len(loaded.Windows[0].Groups) != 2 {
+		t.Errorf("invalid loaded windows: %v", loaded.Windows)
+	}
+	if len(loaded.VisibleGroups) != 2 || loaded.VisibleGroups[1] != 1 {
+		t.Errorf("invalid loaded visible groups: %v", loaded.VisibleGroups)
+	}
+}
@@ -119,6 +119,22 @@ func ReStack() {
 	updateEwmhStacking()
 }
 
+// GetStackingOrder returns ids of all stacked windows in bottom-to-top order
+func GetStackingOrder() []xproto.Window {
+	ids := make([]xproto.Window, len(windows))
+	for i, win := range windows {
+		ids[i] = win.Id()
+	}
+	return ids
+}
+
+// GetWindows returns all stacked windows in bottom-to-top order
+func GetWindows() []StackingWindow {
+	wins := make([]StackingWindow, len(windows))
+	copy(wins, windows)
+	return wins
+}
+
 func Remove(win StackingWindow) {
 	for i, w := range windows {
 		if w.Id() == win.Id() {
@@ -159,9 +175,5 @@ func realiseStacking(wins []StackingWindow) {
 }
 
 func updateEwmhStacking() {
-	ids := make([]xproto.Window, len(windows))
-	for i, win := range windows {
-		ids[i] = win.Id()
-	}
-	_ = ewmh.ClientListStackingSet(X, ids)
+	_ = ewmh.ClientListStackingSet(X, GetStackingOrder())
 }
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JoinArgs joins arguments back to single string, quoting those which contain spaces
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if strings.ContainsAny(arg, " \t") {
+			quoted[i] = strconv.Quote(arg)
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"image"
 
+	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/xgraphics"
+	"github.com/BurntSushi/xgbutil/xwindow"
 	"github.com/janbina/swm/internal/config"
 )
 
@@ -39,6 +41,27 @@ func CreateTextBox(
 	return ximg, nil
 }
 
+func CreateFilledImage(x *xgbutil.XUtil, width, height int, color uint32) *xgraphics.Image {
+	ximg := xgraphics.New(x, image.Rect(0, 0, width, height))
+	ximg.For(func(x, y int) xgraphics.BGRA {
+		return intColor2BGRA(color)
+	})
+	return ximg
+}
+
+// SetBackgroundImage sets image as background of the window, so the window is repainted by X server itself
+// Image is destroyed afterwards
+func SetBackgroundImage(win *xwindow.Window, ximg *xgraphics.Image) error {
+	defer ximg.Destroy()
+	if err := ximg.XSurfaceSet(win.Id); err != nil {
+		return err
+	}
+	ximg.XDraw()
+	win.Change(xproto.CwBackPixmap, uint32(ximg.Pixmap))
+	win.ClearAll()
+	return nil
+}
+
 func intColor2BGRA(color uint32) xgraphics.BGRA {
 	B := color & 0xFF
 	G := (color >> 8) & 0xFF
@@ -25,6 +25,11 @@ func QueryPointer(x *xgbutil.XUtil) (*QueryPointerResponse, error) {
 	}, nil
 }
 
+// WarpPointer moves pointer to the given root coordinates
+func WarpPointer(x *xgbutil.XUtil, rootX, rootY int) {
+	xproto.WarpPointer(x.Conn(), 0, x.RootWin(), 0, 0, 0, 0, int16(rootX), int16(rootY))
+}
+
 func QueryPointerClient(x *xgbutil.XUtil) (*QueryPointerResponse, error) {
 	r, err := xproto.QueryPointer(x.Conn(), x.RootWin()).Reply()
 	if err != nil {
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// RelativeGeometry describes window geometry relative to the screen
+// Coordinates and size could be either absolute (in pixels) or relative to the screen size (ratios),
+// absolute values take precedence
+// Origin specifies point on the screen to which coordinates are related,
+// it is combination of n, s, w, e - missing direction on an axis means center
+type RelativeGeometry struct {
+	Origin         string
+	X, Y, W, H     int
+	XR, YR, WR, HR float64
+}
+
+// Resolve computes real geometry on the screen, window geometry is used when width/height is not specified
+func (g *RelativeGeometry) Resolve(screen, win xrect.Rect) (x, y, w, h int) {
+	x, y, w, h = g.X, g.Y, g.W, g.H
+
+	if x == 0 {
+		x = int(g.XR * float64(screen.Width()))
+	}
+
+	if y == 0 {
+		y = int(g.YR * float64(screen.Height()))
+	}
+
+	if w == 0 {
+		w = int(g.WR * float64(screen.Width()))
+	}
+
+	if h == 0 {
+		h = int(g.HR * float64(screen.Height()))
+	}
+
+	if w <= 0 {
+		w = win.Width()
+	}
+
+	if h <= 0 {
+		h = win.Height()
+	}
+
+	var realY int
+	if strings.Contains(g.Origin, "n") {
+		realY = screen.Y() + y
+	} else if strings.Contains(g.Origin, "s") {
+		realY = screen.Y() + screen.Height() - y - h
+	} else { //center
+		realY = screen.Y() + screen.Height()/2 - h/2 + y
+	}
+
+	var realX int
+	if strings.Contains(g.Origin, "w") {
+		realX = screen.X() + x
+	} else if strings.Contains(g.Origin, "e") {
+		realX = screen.X() + screen.Width() - x - w
+	} else { //center
+		realX = screen.X() + screen.Width()/2 - w/2 + x
+	}
+
+	return realX, realY, w, h
+}
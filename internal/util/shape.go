@@ -0,0 +1,56 @@
+package util
+
+import (
+	"log"
+	"math"
+
+	"github.com/BurntSushi/xgb/shape"
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+)
+
+var shapeSupported = false
+
+// InitShape initializes X Shape extension, windows cannot have rounded corners without it
+func InitShape(X *xgbutil.XUtil) {
+	if err := shape.Init(X.Conn()); err != nil {
+		log.Printf("Shape extension is not available, rounded corners are disabled: %s", err)
+		return
+	}
+	shapeSupported = true
+}
+
+// SetRoundedCorners shapes window of given size, so that it has corners rounded with given radius
+// Zero radius resets the window to its rectangular shape
+func SetRoundedCorners(X *xgbutil.XUtil, win xproto.Window, width, height, radius int) {
+	if !shapeSupported {
+		return
+	}
+	if radius > width/2 {
+		radius = width / 2
+	}
+	if radius > height/2 {
+		radius = height / 2
+	}
+	if radius <= 0 {
+		shape.Mask(X.Conn(), shape.SoSet, shape.SkBounding, win, 0, 0, xproto.PixmapNone)
+		return
+	}
+
+	rects := make([]xproto.Rectangle, 0, 2*radius+1)
+	for y := 0; y < radius; y++ {
+		// horizontal distance of the arc from the window edge in the middle of this row
+		dy := float64(radius) - float64(y) - 0.5
+		dx := radius - int(math.Round(math.Sqrt(float64(radius*radius)-dy*dy)))
+		w := uint16(width - 2*dx)
+		rects = append(rects,
+			xproto.Rectangle{X: int16(dx), Y: int16(y), Width: w, Height: 1},
+			xproto.Rectangle{X: int16(dx), Y: int16(height - 1 - y), Width: w, Height: 1},
+		)
+	}
+	rects = append(rects, xproto.Rectangle{
+		X: 0, Y: int16(radius), Width: uint16(width), Height: uint16(height - 2*radius),
+	})
+
+	shape.Rectangles(X.Conn(), shape.SoSet, shape.SkBounding, xproto.ClipOrderingUnsorted, win, 0, 0, rects)
+}
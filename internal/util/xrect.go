@@ -33,6 +33,18 @@ func MinMovement(rect xrect.Rect, rects []xrect.Rect, minOverlap int) (x, y int)
 	return minX, minY
 }
 
+// Geometry is json friendly representation of xrect.Rect
+type Geometry struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func NewGeometry(rect xrect.Rect) Geometry {
+	return Geometry{X: rect.X(), Y: rect.Y(), Width: rect.Width(), Height: rect.Height()}
+}
+
 func neededMovement(a1, aS, b1, bS, minOverlap int) int {
 	a2 := a1 + aS
 	b2 := b1 + bS
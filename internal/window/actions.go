@@ -12,6 +12,7 @@ import (
 	"github.com/BurntSushi/xgbutil/xrect"
 	"github.com/janbina/swm/internal/config"
 	"github.com/janbina/swm/internal/decoration"
+	"github.com/janbina/swm/internal/events"
 	"github.com/janbina/swm/internal/heads"
 	"github.com/janbina/swm/internal/stack"
 	"github.com/janbina/swm/internal/util"
@@ -74,6 +75,14 @@ func (w *Window) moveResizeInternal(validate bool, x, y, width, height int, flag
 
 		parentWidth := innerWidth + extents.Left + extents.Right
 		parentHeight := innerHeight + extents.Top + extents.Bottom
+		clientFlags := f
+
+		if w.shaded {
+			// only decorations are visible, unmapped client keeps its height for unshading
+			innerHeight = 0
+			parentHeight = extents.Top + extents.Bottom
+			clientFlags &^= ConfigHeight
+		}
 
 		w.parent.MROpt(f, x, y, parentWidth, parentHeight)
 
@@ -84,8 +93,14 @@ func (w *Window) moveResizeInternal(validate bool, x, y, width, height int, flag
 			log.Printf("Bad window size")
 		}
 
-		w.win.MROpt(f, newRect.X(), newRect.Y(), newRect.Width(), newRect.Height())
+		w.win.MROpt(clientFlags, newRect.X(), newRect.Y(), newRect.Width(), newRect.Height())
 		w.sendConfigureNotify()
+		w.updateShape(parentWidth, parentHeight)
+	}
+	if events.Wanted(events.GeometryChanged) {
+		if g, err := w.Geometry(); err == nil {
+			events.GeometryChangedEvent(w.win.Id, g)
+		}
 	}
 }
 
@@ -93,6 +108,7 @@ func (w *Window) MaximizeVert() {
 	if w.maxedVert || w.fullscreen {
 		return
 	}
+	w.UnShade()
 	w.maxedVert = true
 	w.AddStates("_NET_WM_STATE_MAXIMIZED_VERT")
 
@@ -163,6 +179,16 @@ func (w *Window) MaximizeHorzToggle() {
 	}
 }
 
+func (w *Window) MaximizeToggle() {
+	if w.maxedVert && w.maxedHorz {
+		w.UnMaximizeVert()
+		w.UnMaximizeHorz()
+	} else {
+		w.MaximizeVert()
+		w.MaximizeHorz()
+	}
+}
+
 func (w *Window) IconifyToggle() {
 	if w.iconified {
 		w.DeIconify()
@@ -249,6 +275,7 @@ func (w *Window) Fullscreen() {
 	if w.fullscreen {
 		return
 	}
+	w.UnShade()
 	w.fullscreen = true
 	w.AddStates("_NET_WM_STATE_FULLSCREEN")
 
@@ -276,6 +303,54 @@ func (w *Window) FullscreenToggle() {
 	}
 }
 
+// Shade unmaps the client and shrinks the frame, so that only its decorations (e.g. title bar) are visible
+func (w *Window) Shade() {
+	if w.shaded || w.fullscreen {
+		return
+	}
+	w.SaveWindowState(StatePriorShade)
+	w.shaded = true
+	w.AddStates("_NET_WM_STATE_SHADED")
+
+	w.win.Unmap()
+	if g, err := w.Geometry(); err == nil {
+		w.moveResizeInternal(false, g.X(), g.Y(), g.Width(), g.Height())
+	}
+	w.updateFrameExtents()
+	if w.focused {
+		w.ApplyFocus()
+	}
+}
+
+func (w *Window) UnShade() {
+	if !w.shaded {
+		return
+	}
+	w.shaded = false
+	w.RemoveStates("_NET_WM_STATE_SHADED")
+
+	w.LoadWindowState(StatePriorShade)
+	w.updateFrameExtents()
+	if w.mapped {
+		w.win.Map()
+	}
+	if w.focused {
+		w.ApplyFocus()
+	}
+}
+
+func (w *Window) ShadeToggle() {
+	if w.shaded {
+		w.UnShade()
+	} else {
+		w.Shade()
+	}
+}
+
+func (w *Window) IsShaded() bool {
+	return w.shaded
+}
+
 func (w *Window) UnSkipTaskbar() {
 	w.skipTaskbar = false
 	w.RemoveStates("_NET_WM_STATE_SKIP_TASKBAR")
@@ -325,27 +400,38 @@ func (w *Window) ConfigureRequest(e xevent.ConfigureRequestEvent) {
 // RootGeometryChanged moves window based on changes to root geometry
 // New monitors might have been added/removed and resolution could have changed
 // We unfullscreen and unmaximize window, so it restores its original geometry,
-// than we check if window overlaps with any monitor and if not, move it so it does
+// than we move it to the head given by move keeping its relative geometry, if there is any,
+// check if window overlaps with any monitor and if not, move it so it does
 // and finally restore maximized and fullscreen states
-func (w *Window) RootGeometryChanged() {
+func (w *Window) RootGeometryChanged(move HeadMove) {
+	w.keepingStates(func() {
+		w.applyHeadMove(move)
+
+		g, _ := w.Geometry()
+
+		dX, dY := util.MinMovement(g, heads.HeadsStruts, 50)
+		flags := 0
+		if dX != 0 {
+			flags |= ConfigX
+		}
+		if dY != 0 {
+			flags |= ConfigY
+		}
+		if flags != 0 {
+			w.MoveResize(true, g.X()+dX, g.Y()+dY, 0, 0, flags)
+		}
+	})
+}
+
+// keepingStates calls f with window unmaximized and not fullscreen, the states are restored afterwards,
+// so they are applied to the head window is on after f
+func (w *Window) keepingStates(f func()) {
 	maxedVert, maxedHorz, fullscreen := w.maxedVert, w.maxedHorz, w.fullscreen
 	w.UnFullscreen()
 	w.UnMaximizeVert()
 	w.UnMaximizeHorz()
 
-	g, _ := w.Geometry()
-
-	dX, dY := util.MinMovement(g, heads.HeadsStruts, 50)
-	flags := 0
-	if dX != 0 {
-		flags |= ConfigX
-	}
-	if dY != 0 {
-		flags |= ConfigY
-	}
-	if flags != 0 {
-		w.MoveResize(true, g.X()+dX, g.Y()+dY, 0, 0, flags)
-	}
+	f()
 
 	if maxedVert {
 		w.MaximizeVert()
@@ -361,6 +447,13 @@ func (w *Window) RootGeometryChanged() {
 func (w *Window) sendConfigureNotify() {
 	e := w.GetFrameExtents()
 	if g, err := w.Geometry(); err == nil {
+		height := g.Height() - e.Top - e.Bottom
+		if w.shaded {
+			// frame is shrunk, but client keeps its size
+			if cg, err := w.win.Geometry(); err == nil {
+				height = cg.Height()
+			}
+		}
 		e := xproto.ConfigureNotifyEvent{
 			Event:            w.win.Id,
 			Window:           w.win.Id,
@@ -368,7 +461,7 @@ func (w *Window) sendConfigureNotify() {
 			X:                int16(g.X() + e.Left),
 			Y:                int16(g.Y() + e.Top),
 			Width:            uint16(g.Width() - e.Left - e.Right),
-			Height:           uint16(g.Height() - e.Top - e.Bottom),
+			Height:           uint16(height),
 			BorderWidth:      0,
 			OverrideRedirect: false,
 		}
@@ -382,6 +475,36 @@ func (w *Window) sendConfigureNotify() {
 	}
 }
 
+// Redecorate applies changed decoration config (border sizes and colors) to the window
+// Frame keeps its geometry, client window is resized to fit into it
+func (w *Window) Redecorate() {
+	w.updateFrameExtents()
+	switch {
+	case w.demandsAttention:
+		w.decorations.Attention()
+	case w.focused:
+		w.decorations.Active()
+	default:
+		w.decorations.InActive()
+	}
+	if g, err := w.Geometry(); err == nil {
+		w.moveResizeInternal(false, g.X(), g.Y(), g.Width(), g.Height())
+	}
+}
+
+// updateShape rounds corners of decorated windows, fullscreen windows stay rectangular
+func (w *Window) updateShape(width, height int) {
+	radius := config.CornerRadius
+	if w.fullscreen || len(w.decorations) == 0 {
+		radius = 0
+	}
+	if radius == 0 && !w.shaped {
+		return
+	}
+	w.shaped = radius > 0
+	util.SetRoundedCorners(w.win.X, w.parent.Id, width, height, radius)
+}
+
 func (w *Window) updateFrameExtents() {
 	_ = ewmh.FrameExtentsSet(w.win.X, w.win.Id, w.GetFrameExtents())
 }
@@ -15,7 +15,7 @@ import (
 	"github.com/janbina/swm/internal/config"
 	"github.com/janbina/swm/internal/decoration"
 	"github.com/janbina/swm/internal/focus"
-	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/rules"
 	"github.com/janbina/swm/internal/stack"
 	"github.com/janbina/swm/internal/util"
 )
@@ -26,9 +26,11 @@ type Window struct {
 	infoWin     *xwindow.Window
 	infoTimer   *time.Timer
 	decorations decoration.Decorations
+	titleBar    *titleBar
 	moveState   *MoveState
 	resizeState *ResizeState
 	savedStates map[state]windowState
+	actions     *rules.Actions
 
 	maxedVert        bool
 	maxedHorz        bool
@@ -41,8 +43,16 @@ type Window struct {
 	fullscreen       bool
 	skipTaskbar      bool
 	skipPager        bool
+	shaded           bool
+	// whether the frame has rounded corners
+	shaped bool
+	// head the window was on before it was disconnected
+	lastHead *headMemory
+	// frames of other visible windows, provided by the window manager
+	visibleFrames func() []xrect.Rect
 
 	name         string
+	class        *icccm.WmClass
 	protocols    util.StringSet
 	hints        *icccm.Hints
 	normalHints  *icccm.NormalHints
@@ -54,6 +64,8 @@ type Window struct {
 type MoveState struct {
 	rx, ry    int
 	startGeom xrect.Rect
+	// area the window will be tiled to when dropped, nil if it won't be tiled
+	tileRect xrect.Rect
 }
 
 type ResizeState struct {
@@ -62,13 +74,19 @@ type ResizeState struct {
 	startGeom xrect.Rect
 }
 
-func New(x *xgbutil.XUtil, xWin xproto.Window) *Window {
+// New manages given client window, visibleFrames returns frame geometries of other visible windows,
+// which are used for placement and snapping
+func New(x *xgbutil.XUtil, xWin xproto.Window, visibleFrames func() []xrect.Rect) *Window {
 	window := &Window{
-		win: xwindow.New(x, xWin),
+		win:           xwindow.New(x, xWin),
+		visibleFrames: visibleFrames,
 	}
 
 	window.fetchXProperties()
 
+	window.actions = rules.Match(window.ruleProperties())
+	window.applyRuleStates()
+
 	window.savedStates = make(map[state]windowState)
 
 	_ = util.SetBorderWidth(window.win, 0)
@@ -80,27 +98,32 @@ func New(x *xgbutil.XUtil, xWin xproto.Window) *Window {
 
 	window.parent, _ = reparent(x, xWin)
 
-	if window.normalHints.Flags&icccm.SizeHintUSPosition == 0 &&
-		window.normalHints.Flags&icccm.SizeHintPPosition == 0 {
-		if pointer, err := util.QueryPointer(x); err == nil {
-			if head, err := heads.GetHeadForPointerStruts(pointer.X, pointer.Y); err == nil {
-				xGap := head.Width() - g.Width()
-				yGap := head.Height() - g.Height()
-				g.XSet(head.X() + xGap/2)
-				g.YSet(head.Y() + yGap/2)
-			}
-		}
-	}
-
 	decorations := make(decoration.Decorations, 0)
 
 	if window.shouldDecorate() {
+		// handles have to be first, they are placed over the borders
+		decorations = append(decorations, createResizeHandles(window))
 		decorations = append(decorations, createBorders(window.parent)...)
 	}
 
 	window.decorations = decorations
 
-	window.MoveResizeWinSize(true, g.X(), g.Y(), g.Width(), g.Height())
+	if window.shouldHaveTitleBar() {
+		if window.titleBar = createTitleBar(window); window.titleBar != nil {
+			window.decorations = append(window.decorations, window.titleBar)
+		}
+	}
+
+	if window.normalHints.Flags&icccm.SizeHintUSPosition == 0 &&
+		window.normalHints.Flags&icccm.SizeHintPPosition == 0 {
+		window.place(g)
+	}
+
+	if window.actions.Geometry != nil {
+		window.applyRuleGeometry(g)
+	} else {
+		window.MoveResizeWinSize(true, g.X(), g.Y(), g.Width(), g.Height())
+	}
 
 	if !window.types.Any("_NET_WM_WINDOW_TYPE_DESKTOP", "_NET_WM_WINDOW_TYPE_DOCK") {
 		focus.InitialAdd(window)
@@ -133,6 +156,7 @@ func reparent(X *xgbutil.XUtil, xWin xproto.Window) (*xwindow.Window, error) {
 		xproto.EventMaskSubstructureRedirect|
 			xproto.EventMaskButtonPress|
 			xproto.EventMaskButtonRelease|
+			xproto.EventMaskEnterWindow|
 			xproto.EventMaskFocusChange,
 	)
 	if err != nil {
@@ -160,6 +184,10 @@ func (w *Window) Id() xproto.Window {
 	return w.win.Id
 }
 
+func (w *Window) Name() string {
+	return w.name
+}
+
 func (w *Window) Geometry() (xrect.Rect, error) {
 	return w.parent.Geometry()
 }
@@ -170,7 +198,9 @@ func (w *Window) Listen(evMasks ...int) error {
 
 func (w *Window) Map() {
 	w.parent.Map()
-	w.win.Map()
+	if !w.shaded {
+		w.win.Map()
+	}
 	w.mapped = true
 	w.iconified = false
 	_ = w.SetIcccmState(icccm.StateNormal)
@@ -209,11 +239,33 @@ func (w *Window) Destroy() {
 }
 
 func (w *Window) Destroyed() {
+	if w.inKeyboardMode() {
+		w.keyboardEnd()
+	}
 	_ = w.SetIcccmState(icccm.StateWithdrawn)
 	focus.Remove(w)
 	stack.Remove(w)
 	xproto.ReparentWindow(w.win.X.Conn(), w.win.Id, w.win.X.RootWin(), 0, 0)
 	w.win.Destroy()
+	w.decorations.Destroy()
+	w.parent.Destroy()
+}
+
+// Unwind releases the window when swm is being restarted
+// Client is reparented back to root, keeping its position and mapping state, and the frame is destroyed,
+// the client is managed again by the new swm process
+func (w *Window) Unwind() {
+	if w.inKeyboardMode() {
+		w.keyboardEnd()
+	}
+	x, y := 0, 0
+	if g, err := w.Geometry(); err == nil {
+		e := w.GetFrameExtents()
+		x, y = g.X()+e.Left, g.Y()+e.Top
+	}
+	xproto.ReparentWindow(w.win.X.Conn(), w.win.Id, w.win.X.RootWin(), int16(x), int16(y))
+	xproto.ChangeSaveSet(w.win.X.Conn(), xproto.SetModeDelete, w.win.Id)
+	w.decorations.Destroy()
 	w.parent.Destroy()
 }
 
@@ -225,6 +277,25 @@ func (w *Window) IsIconified() bool {
 	return w.iconified
 }
 
+// IsTileable returns whether the window could be arranged by tiling layouts
+// Transient windows and special windows like docks or dialogs are always floating
+func (w *Window) IsTileable() bool {
+	return w.mapped && !w.fullscreen && w.transientFor == 0 && !w.types.Any(
+		"_NET_WM_WINDOW_TYPE_DESKTOP",
+		"_NET_WM_WINDOW_TYPE_DOCK",
+		"_NET_WM_WINDOW_TYPE_SPLASH",
+		"_NET_WM_WINDOW_TYPE_DIALOG",
+		"_NET_WM_WINDOW_TYPE_UTILITY",
+		"_NET_WM_WINDOW_TYPE_TOOLBAR",
+		"_NET_WM_WINDOW_TYPE_MENU",
+		"_NET_WM_WINDOW_TYPE_NOTIFICATION",
+	)
+}
+
+func (w *Window) HasType(types ...string) bool {
+	return w.types.Any(types...)
+}
+
 func (w *Window) IsMouseMoveable() bool {
 	return !w.fullscreen && !w.types.Any("_NET_WM_WINDOW_TYPE_DESKTOP", "_NET_WM_WINDOW_TYPE_DOCK")
 }
@@ -259,10 +330,21 @@ func (w *Window) fetchXProperties() {
 
 	w.types = getTypesForWindow(X, id)
 
+	w.class, err = icccm.WmClassGet(X, id)
+	if err != nil {
+		w.class = &icccm.WmClass{}
+	}
+
+	w.transientFor, _ = icccm.WmTransientForGet(X, id)
+
 	w.name = w.loadName()
 }
 
 func (w *Window) shouldDecorate() bool {
+	if w.actions.Decorate != nil {
+		return *w.actions.Decorate
+	}
+
 	if w.types.Any("_NET_WM_WINDOW_TYPE_DESKTOP", "_NET_WM_WINDOW_TYPE_DOCK", "_NET_WM_WINDOW_TYPE_SPLASH") {
 		return false
 	}
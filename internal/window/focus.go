@@ -6,6 +6,7 @@ import (
 	"github.com/BurntSushi/xgbutil/ewmh"
 	"github.com/BurntSushi/xgbutil/icccm"
 	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/janbina/swm/internal/events"
 	"github.com/janbina/swm/internal/focus"
 	"github.com/janbina/swm/internal/stack"
 	"github.com/janbina/swm/internal/util"
@@ -38,6 +39,9 @@ func (w *Window) PrepareForFocus(tmp bool) {
 }
 
 func (w *Window) Focused() {
+	if !w.focused {
+		events.FocusChangedEvent(w.win.Id)
+	}
 	w.StopAttention()
 	w.focused = true
 	focus.SetFocus(w)
@@ -80,6 +84,11 @@ func (w *Window) FocusToggle() {
 }
 
 func (w *Window) ApplyFocus() {
+	if w.shaded {
+		// client is unmapped, so the frame holds the focus
+		w.parent.Focus()
+		return
+	}
 	if w.CanFocus() {
 		w.win.Focus()
 	}
@@ -102,23 +111,46 @@ func (w *Window) Focus() {
 	focus.Focus(w)
 }
 
-func (w *Window) SetupFocusListeners() {
-	w.handleFocusIn().Connect(w.win.X, w.parent.Id)
-	w.handleFocusOut().Connect(w.win.X, w.parent.Id)
+// SetupFocusListeners tracks focus of the window, onFocus is called when the window gets focus
+// and onIconifyChange when losing focus iconifies back temporarily deiconified window
+func (w *Window) SetupFocusListeners(onFocus, onIconifyChange func(w *Window)) {
+	w.handleFocusIn(onFocus).Connect(w.win.X, w.parent.Id)
+	w.handleFocusOut(onIconifyChange).Connect(w.win.X, w.parent.Id)
+}
+
+// SetupEnterListener calls onEnter when user moves the pointer into the window frame,
+// moving between the client and decorations doesn't count
+func (w *Window) SetupEnterListener(onEnter func(w *Window)) {
+	xevent.EnterNotifyFun(func(X *xgbutil.XUtil, e xevent.EnterNotifyEvent) {
+		if e.Detail != xproto.NotifyDetailInferior && focus.AcceptEnter(e.Mode, e.Sequence) {
+			onEnter(w)
+		}
+	}).Connect(w.win.X, w.parent.Id)
+}
+
+// acceptFocusEvent returns whether focus event on the frame changes focus of the window,
+// shaded window has focus on the frame itself
+func (w *Window) acceptFocusEvent(mode, detail byte) bool {
+	return focus.AcceptClientFocus(mode, detail) || w.shaded && focus.AcceptFrameFocus(mode, detail)
 }
 
-func (w *Window) handleFocusIn() xevent.FocusInFun {
+func (w *Window) handleFocusIn(onFocus func(w *Window)) xevent.FocusInFun {
 	return func(X *xgbutil.XUtil, e xevent.FocusInEvent) {
-		if focus.AcceptClientFocus(e.Mode, e.Detail) {
+		if w.acceptFocusEvent(e.Mode, e.Detail) {
 			w.Focused()
+			onFocus(w)
 		}
 	}
 }
 
-func (w *Window) handleFocusOut() xevent.FocusOutFun {
+func (w *Window) handleFocusOut(onIconifyChange func(w *Window)) xevent.FocusOutFun {
 	return func(X *xgbutil.XUtil, e xevent.FocusOutEvent) {
-		if focus.AcceptClientFocus(e.Mode, e.Detail) {
+		if w.acceptFocusEvent(e.Mode, e.Detail) {
+			iconified := w.iconified
 			w.Unfocused()
+			if w.iconified != iconified {
+				onIconifyChange(w)
+			}
 		}
 	}
 }
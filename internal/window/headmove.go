@@ -0,0 +1,87 @@
+package window
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// HeadMove describes movement of window from one head to another after heads change
+type HeadMove struct {
+	From xrect.Rect
+	To   xrect.Rect
+	// name of the head window leaves because it was disconnected, window returns there when it reappears
+	Lost string
+	// whether window returns to the head it was on before it was disconnected
+	Restore bool
+}
+
+// headMemory holds window geometry relative to the head, so it can be restored on a head of different size
+type headMemory struct {
+	name       string
+	x, y, w, h float64
+}
+
+// LastHead returns name of disconnected head the window was on, or empty string
+func (w *Window) LastHead() string {
+	if w.lastHead == nil {
+		return ""
+	}
+	return w.lastHead.name
+}
+
+// MoveToHead moves window from head from to head to, keeping its geometry relative to the head,
+// maximized and fullscreen states are applied again on the new head
+func (w *Window) MoveToHead(from, to xrect.Rect) {
+	w.keepingStates(func() {
+		w.applyHeadMove(HeadMove{From: from, To: to})
+	})
+}
+
+// applyHeadMove moves window according to move, geometry is scaled to the size of the new head
+// maximized and fullscreen states must be removed before
+func (w *Window) applyHeadMove(move HeadMove) {
+	if move.To == nil {
+		return
+	}
+	g, err := w.Geometry()
+	if err != nil {
+		return
+	}
+
+	var rel headMemory
+	switch {
+	case move.Restore && w.lastHead != nil:
+		rel = *w.lastHead
+		w.lastHead = nil
+	case move.From != nil:
+		rel = relativeGeometry(g, move.From)
+		// keep the original head if window was already moved from a disconnected one
+		if move.Lost != "" && w.lastHead == nil {
+			rel.name = move.Lost
+			w.lastHead = &rel
+		}
+	default:
+		return
+	}
+
+	r := absoluteGeometry(rel, move.To)
+	w.MoveResize(true, r.X(), r.Y(), r.Width(), r.Height())
+}
+
+// absoluteGeometry scales relative geometry to the head, keeping the window inside it
+func absoluteGeometry(rel headMemory, head xrect.Rect) xrect.Rect {
+	width := int(rel.w*float64(head.Width()) + 0.5)
+	height := int(rel.h*float64(head.Height()) + 0.5)
+	x := clamp(head.X()+int(rel.x*float64(head.Width())+0.5), head.X(), head.X()+head.Width()-width)
+	y := clamp(head.Y()+int(rel.y*float64(head.Height())+0.5), head.Y(), head.Y()+head.Height()-height)
+	return xrect.New(x, y, width, height)
+}
+
+func relativeGeometry(g, head xrect.Rect) headMemory {
+	hw, hh := float64(head.Width()), float64(head.Height())
+	return headMemory{
+		x: float64(g.X()-head.X()) / hw,
+		y: float64(g.Y()-head.Y()) / hh,
+		w: float64(g.Width()) / hw,
+		h: float64(g.Height()) / hh,
+	}
+}
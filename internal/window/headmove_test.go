@@ -0,0 +1,60 @@
+package window
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+func geomEquals(a, b xrect.Rect) bool {
+	return a.X() == b.X() && a.Y() == b.Y() && a.Width() == b.Width() && a.Height() == b.Height()
+}
+
+func TestHeadMoveGeometry(t *testing.T) {
+	from := xrect.New(0, 0, 1920, 1080)
+	tests := []struct {
+		name     string
+		g, to    xrect.Rect
+		expected xrect.Rect
+	}{
+		{"same size", xrect.New(100, 50, 800, 600), xrect.New(1920, 0, 1920, 1080), xrect.New(2020, 50, 800, 600)},
+		{"half size", xrect.New(960, 540, 960, 540), xrect.New(1920, 0, 960, 540), xrect.New(2400, 270, 480, 270)},
+		{"with offset", xrect.New(0, 0, 1920, 1080), xrect.New(100, 1080, 1280, 1024), xrect.New(100, 1080, 1280, 1024)},
+		// window partially out of its head is kept inside the new one
+		{"clamped", xrect.New(1800, 1000, 480, 270), xrect.New(1920, 0, 960, 540), xrect.New(2640, 405, 240, 135)},
+		{"clamped left", xrect.New(-100, -50, 480, 270), xrect.New(1920, 0, 960, 540), xrect.New(1920, 0, 240, 135)},
+	}
+	for _, test := range tests {
+		g := absoluteGeometry(relativeGeometry(test.g, from), test.to)
+		if !geomEquals(test.expected, g) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, g)
+		}
+	}
+}
+
+func TestHeadMoveGeometryStaysOnTargetHead(t *testing.T) {
+	// maximized and fullscreen states are applied again on the head the window is on after the move,
+	// so the moved geometry must belong to the target head
+	hs := []xrect.Rect{xrect.New(0, 0, 1920, 1080), xrect.New(1920, 0, 1280, 1024)}
+	for _, g := range []xrect.Rect{
+		xrect.New(0, 0, 1920, 1080),
+		xrect.New(1000, 500, 1000, 600),
+		xrect.New(-50, -50, 2000, 1200),
+	} {
+		moved := absoluteGeometry(relativeGeometry(g, hs[0]), hs[1])
+		if i := xrect.LargestOverlap(moved, hs); i != 1 {
+			t.Errorf("%v moved to %v should be on head 1, is on %d", g, moved, i)
+		}
+	}
+}
+
+func TestHeadMoveGeometryRoundTrip(t *testing.T) {
+	small := xrect.New(1920, 0, 1280, 720)
+	large := xrect.New(0, 0, 2560, 1440)
+	g := xrect.New(320, 180, 1280, 720)
+
+	back := absoluteGeometry(relativeGeometry(absoluteGeometry(relativeGeometry(g, large), small), small), large)
+	if !geomEquals(g, back) {
+		t.Errorf("expected %v, got %v", g, back)
+	}
+}
@@ -0,0 +1,151 @@
+package window
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/keybind"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/keybindings"
+)
+
+const (
+	keyboardStep    = 10
+	keyboardBigStep = 100
+	// info box is hidden when the mode ends, this just has to be long enough
+	keyboardInfoDuration = time.Hour
+)
+
+type keyboardState struct {
+	win       *Window
+	resize    bool
+	startGeom xrect.Rect
+}
+
+var (
+	// keyboard is grabbed globally, so only one window can be in keyboard mode, nil when the mode is not active
+	keyboard *keyboardState
+	// whether key press handler is connected to the dummy window, it is connected only once and never detached,
+	// as other handlers (e.g. selection clear) are connected to the dummy window as well
+	keyboardHandlerConnected bool
+)
+
+// KeyboardMoveBegin starts interactive mode, in which window is moved by arrow keys
+func (w *Window) KeyboardMoveBegin() error {
+	if !w.IsMouseMoveable() {
+		return fmt.Errorf("window cannot be moved")
+	}
+	return w.keyboardBegin(false)
+}
+
+// KeyboardResizeBegin starts interactive mode, in which window is resized by arrow keys
+func (w *Window) KeyboardResizeBegin() error {
+	if !w.IsMouseResizable() {
+		return fmt.Errorf("window cannot be resized")
+	}
+	return w.keyboardBegin(true)
+}
+
+// keyboardBegin grabs keyboard and redirects all key events to the dummy window, where they are handled
+// until the mode is ended by Return (keep current geometry) or Escape (restore starting geometry)
+func (w *Window) keyboardBegin(resize bool) error {
+	if keyboard != nil {
+		return fmt.Errorf("keyboard mode already active")
+	}
+	g, err := w.Geometry()
+	if err != nil {
+		return err
+	}
+	X := w.win.X
+	if err := keybind.DummyGrab(X); err != nil {
+		return err
+	}
+	// the grab belongs to keyboard mode now, key bindings must not release it
+	keybindings.ReleaseGrab()
+	log.Printf("Keyboard move/resize begin, resize: %t", resize)
+
+	keyboard = &keyboardState{
+		win:       w,
+		resize:    resize,
+		startGeom: g,
+	}
+	if !keyboardHandlerConnected {
+		xevent.KeyPressFun(func(X *xgbutil.XUtil, e xevent.KeyPressEvent) {
+			if keyboard != nil {
+				keyboard.win.keyboardStep(e)
+			}
+		}).Connect(X, X.Dummy())
+		keyboardHandlerConnected = true
+	}
+
+	w.Focus()
+	w.Raise()
+	w.showGeometryInfo()
+	return nil
+}
+
+// inKeyboardMode returns whether the window is in keyboard move/resize mode
+func (w *Window) inKeyboardMode() bool {
+	return keyboard != nil && keyboard.win == w
+}
+
+func (w *Window) keyboardStep(e xevent.KeyPressEvent) {
+	step := keyboardStep
+	if e.State&(xproto.ModMaskShift|xproto.ModMaskControl) > 0 {
+		step = keyboardBigStep
+	}
+
+	dx, dy := 0, 0
+	switch keybind.LookupString(w.win.X, 0, e.Detail) {
+	case "Left", "h":
+		dx = -step
+	case "Right", "l":
+		dx = step
+	case "Up", "k":
+		dy = -step
+	case "Down", "j":
+		dy = step
+	case "Return", "KP_Enter":
+		w.keyboardEnd()
+		return
+	case "Escape":
+		g := keyboard.startGeom
+		w.MoveResize(true, g.X(), g.Y(), g.Width(), g.Height())
+		w.keyboardEnd()
+		return
+	default:
+		return
+	}
+
+	g, err := w.Geometry()
+	if err != nil {
+		return
+	}
+	if keyboard.resize {
+		width := max(g.Width()+dx, int(w.normalHints.MinWidth))
+		height := max(g.Height()+dy, int(w.normalHints.MinHeight))
+		w.MoveResize(true, g.X(), g.Y(), width, height, ConfigSize)
+	} else {
+		w.Move(g.X()+dx, g.Y()+dy)
+	}
+	w.showGeometryInfo()
+}
+
+func (w *Window) keyboardEnd() {
+	log.Printf("Keyboard move/resize end")
+	keybind.DummyUngrab(w.win.X)
+	keyboard = nil
+	w.HideInfoBox()
+}
+
+func (w *Window) showGeometryInfo() {
+	g, err := w.Geometry()
+	if err != nil {
+		return
+	}
+	w.ShowInfoBox(fmt.Sprintf("%d,%d %dx%d", g.X(), g.Y(), g.Width(), g.Height()), keyboardInfoDuration)
+}
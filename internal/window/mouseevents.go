@@ -117,14 +117,27 @@ func dragMoveStep(w *Window) xgbutil.MouseDragFun {
 		g := w.moveState.startGeom
 		x := g.X() + rx - w.moveState.rx
 		y := g.Y() + ry - w.moveState.ry
+		x, y = w.snapMove(x, y, g.Width(), g.Height())
 
 		w.Move(x, y)
+
+		if r := edgeTileRect(rx, ry); r != nil {
+			showTilePreview(X, r)
+			w.moveState.tileRect = r
+		} else if w.moveState.tileRect != nil {
+			hideTilePreview()
+			w.moveState.tileRect = nil
+		}
 	}
 }
 
 func dragMoveEnd(w *Window) xgbutil.MouseDragFun {
 	return func(X *xgbutil.XUtil, rx, ry, ex, ey int) {
 		log.Printf("Drag move end: %d, %d, %d, %d", rx, ry, ex, ey)
+		if r := w.moveState.tileRect; r != nil {
+			hideTilePreview()
+			w.MoveResize(true, r.X(), r.Y(), r.Width(), r.Height())
+		}
 		w.moveState = nil
 	}
 }
@@ -257,6 +270,7 @@ func dragResizeStep(win *Window) xgbutil.MouseDragFun {
 				h += yDiff
 			}
 		}
+		x, y, w, h = win.snapResize(x, y, w, h, changeX, changeY, changeW, changeH)
 
 		flags := ConfigAll
 		if w < int(win.normalHints.MinWidth) {
@@ -0,0 +1,214 @@
+package window
+
+import (
+	"math"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/focus"
+	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/stack"
+	"github.com/janbina/swm/internal/util"
+)
+
+const cascadeStep = 30
+
+// position of next cascaded window, counted in cascade steps from the head corner
+var cascadeIndex = 0
+
+// place sets position of new window (its frame) according to the configured placement policy
+// g is client geometry, which will be used as frame position
+func (w *Window) place(g xrect.Rect) {
+	e := w.GetFrameExtents()
+	frame := xrect.New(g.X(), g.Y(), g.Width()+e.Left+e.Right, g.Height()+e.Top+e.Bottom)
+
+	var x, y int
+	var ok bool
+
+	switch config.Placement {
+	case config.PlacementFocused:
+		x, y, ok = placeOnFocusedHead(frame)
+	case config.PlacementCascade:
+		x, y, ok = w.placeCascade(frame)
+	case config.PlacementSmart:
+		x, y, ok = w.placeSmart(frame, w.visibleFrames())
+	case config.PlacementUnderMouse:
+		x, y, ok = w.placeUnderMouse(frame)
+	case config.PlacementParent:
+		x, y, ok = w.placeOnParent(frame)
+	}
+
+	if !ok {
+		x, y, ok = w.placeOnPointerHead(frame)
+	}
+
+	if ok {
+		g.XSet(x)
+		g.YSet(y)
+	}
+}
+
+func (w *Window) placeOnPointerHead(frame xrect.Rect) (int, int, bool) {
+	head, err := w.getPointerHead()
+	if err != nil {
+		return 0, 0, false
+	}
+	x, y := centerIn(frame, head)
+	return x, y, true
+}
+
+func placeOnFocusedHead(frame xrect.Rect) (int, int, bool) {
+	active, ok := focus.Current().(*Window)
+	if !ok {
+		return 0, 0, false
+	}
+	g, err := active.Geometry()
+	if err != nil {
+		return 0, 0, false
+	}
+	head, err := heads.GetHeadForRectStruts(g)
+	if err != nil {
+		return 0, 0, false
+	}
+	x, y := centerIn(frame, head)
+	return x, y, true
+}
+
+func (w *Window) placeCascade(frame xrect.Rect) (int, int, bool) {
+	head, err := w.getPointerHead()
+	if err != nil {
+		return 0, 0, false
+	}
+	offset := cascadeIndex * cascadeStep
+	if offset+frame.Width() > head.Width() || offset+frame.Height() > head.Height() {
+		// we would get out of the head, start again from the corner
+		cascadeIndex = 0
+		offset = 0
+	}
+	cascadeIndex++
+	return head.X() + offset, head.Y() + offset, true
+}
+
+func (w *Window) placeUnderMouse(frame xrect.Rect) (int, int, bool) {
+	pointer, err := util.QueryPointer(w.win.X)
+	if err != nil {
+		return 0, 0, false
+	}
+	head, err := heads.GetHeadForPointerStruts(pointer.X, pointer.Y)
+	if err != nil {
+		return 0, 0, false
+	}
+	x := clamp(pointer.X-frame.Width()/2, head.X(), head.X()+head.Width()-frame.Width())
+	y := clamp(pointer.Y-frame.Height()/2, head.Y(), head.Y()+head.Height()-frame.Height())
+	return x, y, true
+}
+
+// placeSmart finds position on the head under the pointer, where the window covers the least area of others
+// windows. Candidate positions are head corners and positions next to edges of other windows.
+func (w *Window) placeSmart(frame xrect.Rect, others []xrect.Rect) (int, int, bool) {
+	head, err := w.getPointerHead()
+	if err != nil {
+		return 0, 0, false
+	}
+
+	maxX := head.X() + head.Width() - frame.Width()
+	maxY := head.Y() + head.Height() - frame.Height()
+	xs := []int{head.X(), maxX}
+	ys := []int{head.Y(), maxY}
+	for _, o := range others {
+		xs = append(xs, o.X()+o.Width(), o.X()-frame.Width())
+		ys = append(ys, o.Y()+o.Height(), o.Y()-frame.Height())
+	}
+
+	bestX, bestY, bestOverlap := head.X(), head.Y(), math.MaxInt64
+	for _, y := range ys {
+		y = clamp(y, head.Y(), maxY)
+		for _, x := range xs {
+			x = clamp(x, head.X(), maxX)
+			overlap := 0
+			for _, o := range others {
+				overlap += overlapArea(x, y, frame.Width(), frame.Height(), o)
+			}
+			// prefer positions closer to the top left corner when overlaps are equal
+			if overlap < bestOverlap ||
+				overlap == bestOverlap && (y < bestY || y == bestY && x < bestX) {
+				bestX, bestY, bestOverlap = x, y, overlap
+			}
+		}
+	}
+
+	return bestX, bestY, true
+}
+
+// placeOnParent centers transient window on its parent, but keeps it inside parent's head,
+// dialog without parent is centered on the head of focused window
+func (w *Window) placeOnParent(frame xrect.Rect) (int, int, bool) {
+	var parent *Window
+	for _, sw := range stack.GetWindows() {
+		if win, ok := sw.(*Window); ok && w.transientFor != 0 && win.Id() == w.transientFor {
+			parent = win
+		}
+	}
+	if parent == nil {
+		if w.types.Any("_NET_WM_WINDOW_TYPE_DIALOG") {
+			return placeOnFocusedHead(frame)
+		}
+		return 0, 0, false
+	}
+	pg, err := parent.Geometry()
+	if err != nil {
+		return 0, 0, false
+	}
+	x, y := centerIn(frame, pg)
+	if head, err := heads.GetHeadForRectStruts(pg); err == nil {
+		x = clamp(x, head.X(), head.X()+head.Width()-frame.Width())
+		y = clamp(y, head.Y(), head.Y()+head.Height()-frame.Height())
+	}
+	return x, y, true
+}
+
+func (w *Window) getPointerHead() (xrect.Rect, error) {
+	pointer, err := util.QueryPointer(w.win.X)
+	if err != nil {
+		return nil, err
+	}
+	return heads.GetHeadForPointerStruts(pointer.X, pointer.Y)
+}
+
+func centerIn(rect, container xrect.Rect) (int, int) {
+	return container.X() + (container.Width()-rect.Width())/2, container.Y() + (container.Height()-rect.Height())/2
+}
+
+func overlapArea(x, y, width, height int, rect xrect.Rect) int {
+	w := min(x+width, rect.X()+rect.Width()) - max(x, rect.X())
+	h := min(y+height, rect.Y()+rect.Height()) - max(y, rect.Y())
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+// clamp keeps val between low and high, low wins if the range is empty (window is bigger than the head)
+func clamp(val, low, high int) int {
+	if val > high {
+		val = high
+	}
+	if val < low {
+		val = low
+	}
+	return val
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -10,6 +10,8 @@ import (
 
 var propertyHandlers = map[string]func(win *Window){
 	"WM_NORMAL_HINTS": handleNormalHints,
+	"_NET_WM_NAME":    handleName,
+	"WM_NAME":         handleName,
 }
 
 func (w *Window) HandlePropertyNotify(e xevent.PropertyNotifyEvent) {
@@ -26,6 +28,13 @@ func (w *Window) HandlePropertyNotify(e xevent.PropertyNotifyEvent) {
 	}
 }
 
+func handleName(w *Window) {
+	w.name = w.loadName()
+	if w.titleBar != nil {
+		w.titleBar.render()
+	}
+}
+
 func handleNormalHints(w *Window) {
 	if h, err := icccm.WmNormalHintsGet(w.win.X, w.win.Id); err == nil {
 		w.normalHints = h
@@ -0,0 +1,191 @@
+package window
+
+import (
+	"log"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/mousebind"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/decoration"
+)
+
+// distance from frame corner, in which dragging the border resizes the window in both directions
+const resizeCornerSize = 20
+
+// resizeHandles are invisible windows covering borders (and optional margin inside them), dragging them
+// resizes the window. They take no space, so they have to be applied first to get the whole frame.
+type resizeHandles struct {
+	w       *Window
+	handles map[decoration.Position]*resizeHandle
+	width   int
+	height  int
+}
+
+type resizeHandle struct {
+	win       *xwindow.Window
+	rect      xrect.Rect
+	direction int
+}
+
+func createResizeHandles(w *Window) *resizeHandles {
+	r := &resizeHandles{
+		w:       w,
+		handles: make(map[decoration.Position]*resizeHandle),
+	}
+	for _, pos := range []decoration.Position{decoration.Top, decoration.Bottom, decoration.Left, decoration.Right} {
+		if h := r.createHandle(pos); h != nil {
+			r.handles[pos] = h
+		}
+	}
+	return r
+}
+
+func (r *resizeHandles) createHandle(pos decoration.Position) *resizeHandle {
+	X := r.w.win.X
+	win, err := xwindow.Generate(X)
+	if err != nil {
+		log.Printf("Cannot create resize handle: %s", err)
+		return nil
+	}
+	err = xproto.CreateWindowChecked(
+		X.Conn(), 0, win.Id, r.w.parent.Id, 0, 0, 1, 1, 0,
+		xproto.WindowClassInputOnly, 0,
+		xproto.CwEventMask, []uint32{xproto.EventMaskPointerMotion},
+	).Check()
+	if err != nil {
+		log.Printf("Cannot create resize handle: %s", err)
+		return nil
+	}
+
+	h := &resizeHandle{win: win, rect: xrect.New(0, 0, 1, 1), direction: -1}
+
+	// cursor changes when pointer moves to the corner
+	xevent.MotionNotifyFun(func(X *xgbutil.XUtil, e xevent.MotionNotifyEvent) {
+		r.updateCursor(h, r.direction(pos, h, int(e.EventX), int(e.EventY)))
+	}).Connect(X, win.Id)
+
+	mousebind.Drag(
+		X, X.Dummy(), win.Id, "1", true,
+		func(X *xgbutil.XUtil, rx, ry, ex, ey int) (bool, xproto.Cursor) {
+			return dragResizeBegin(r.w, r.direction(pos, h, ex, ey))(X, rx, ry, ex, ey)
+		},
+		dragResizeStep(r.w), dragResizeEnd(r.w),
+	)
+
+	return h
+}
+
+// direction returns resize direction for position inside handle
+func (r *resizeHandles) direction(pos decoration.Position, h *resizeHandle, ex, ey int) int {
+	x, y := h.rect.X()+ex, h.rect.Y()+ey
+	nearLeft := x < resizeCornerSize
+	nearRight := x >= r.width-resizeCornerSize
+	nearTop := y < resizeCornerSize
+	nearBottom := y >= r.height-resizeCornerSize
+
+	switch pos {
+	case decoration.Top:
+		return pickDirection(nearLeft, nearRight, ewmh.SizeTopLeft, ewmh.SizeTopRight, ewmh.SizeTop)
+	case decoration.Bottom:
+		return pickDirection(nearLeft, nearRight, ewmh.SizeBottomLeft, ewmh.SizeBottomRight, ewmh.SizeBottom)
+	case decoration.Left:
+		return pickDirection(nearTop, nearBottom, ewmh.SizeTopLeft, ewmh.SizeBottomLeft, ewmh.SizeLeft)
+	default:
+		return pickDirection(nearTop, nearBottom, ewmh.SizeTopRight, ewmh.SizeBottomRight, ewmh.SizeRight)
+	}
+}
+
+func pickDirection(first, second bool, firstDir, secondDir, otherwise int) int {
+	if first {
+		return firstDir
+	}
+	if second {
+		return secondDir
+	}
+	return otherwise
+}
+
+func (r *resizeHandles) updateCursor(h *resizeHandle, direction int) {
+	if h.direction != direction {
+		h.direction = direction
+		h.win.Change(xproto.CwCursor, uint32(getCursorForDirection(direction)))
+	}
+}
+
+func (r *resizeHandles) ApplyRect(c *decoration.WinConfig, rect xrect.Rect, f int) xrect.Rect {
+	if c.Fullscreen {
+		for _, h := range r.handles {
+			h.win.Unmap()
+		}
+		return rect
+	}
+
+	x, y, w, h := rect.Pieces()
+	r.width, r.height = w, h
+
+	m := config.ResizeMargin
+	rects := map[decoration.Position]xrect.Rect{
+		decoration.Top:    xrect.New(x, y, w, config.BorderTop.Size+m),
+		decoration.Bottom: xrect.New(x, y+h-config.BorderBottom.Size-m, w, config.BorderBottom.Size+m),
+		decoration.Left:   xrect.New(x, y, config.BorderLeft.Size+m, h),
+		decoration.Right:  xrect.New(x+w-config.BorderRight.Size-m, y, config.BorderRight.Size+m, h),
+	}
+
+	for pos, handle := range r.handles {
+		hr := rects[pos]
+		if hr.Width() <= 0 || hr.Height() <= 0 {
+			handle.win.Unmap()
+			continue
+		}
+		handle.rect = hr
+		handle.win.MoveResize(hr.Pieces())
+		// handles have to be above the client and other decorations
+		handle.win.Stack(xproto.StackModeAbove)
+		handle.win.Map()
+		r.updateCursor(handle, r.direction(pos, handle, hr.Width()/2, hr.Height()/2))
+	}
+
+	return rect
+}
+
+func (r *resizeHandles) WidthNeeded(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (r *resizeHandles) HeightNeeded(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (r *resizeHandles) Left(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (r *resizeHandles) Right(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (r *resizeHandles) Top(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (r *resizeHandles) Bottom(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (r *resizeHandles) Active() {}
+
+func (r *resizeHandles) InActive() {}
+
+func (r *resizeHandles) Attention() {}
+
+func (r *resizeHandles) Destroy() {
+	for _, h := range r.handles {
+		mousebind.Detach(h.win.X, h.win.Id)
+		h.win.Destroy()
+	}
+}
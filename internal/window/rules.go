@@ -0,0 +1,76 @@
+package window
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/rules"
+)
+
+// InitialGroup returns group the window should be placed to, if specified by some rule
+func (w *Window) InitialGroup() (int, bool) {
+	if w.actions.Group == nil {
+		return 0, false
+	}
+	return *w.actions.Group, true
+}
+
+// FocusOnMap returns whether the window should be focused when it is mapped for the first time
+func (w *Window) FocusOnMap() bool {
+	return w.actions.FocusOnMap == nil || *w.actions.FocusOnMap
+}
+
+func (w *Window) ruleProperties() *rules.Properties {
+	return &rules.Properties{
+		Class:     w.class.Class,
+		Instance:  w.class.Instance,
+		Title:     w.name,
+		Types:     w.types,
+		Transient: w.transientFor != 0,
+	}
+}
+
+// applyRuleStates changes initial states of the window, so they are applied when the window is managed
+func (w *Window) applyRuleStates() {
+	a := w.actions
+	if a.Layer != nil {
+		switch *a.Layer {
+		case rules.LayerAbove:
+			w.setInitialState("_NET_WM_STATE_ABOVE", true)
+			w.setInitialState("_NET_WM_STATE_BELOW", false)
+		case rules.LayerBelow:
+			w.setInitialState("_NET_WM_STATE_ABOVE", false)
+			w.setInitialState("_NET_WM_STATE_BELOW", true)
+		default:
+			w.setInitialState("_NET_WM_STATE_ABOVE", false)
+			w.setInitialState("_NET_WM_STATE_BELOW", false)
+		}
+	}
+	if a.SkipTaskbar != nil {
+		w.setInitialState("_NET_WM_STATE_SKIP_TASKBAR", *a.SkipTaskbar)
+	}
+	if a.Fullscreen != nil {
+		w.setInitialState("_NET_WM_STATE_FULLSCREEN", *a.Fullscreen)
+	}
+	if a.Maximized != nil {
+		w.setInitialState("_NET_WM_STATE_MAXIMIZED_VERT", *a.Maximized)
+		w.setInitialState("_NET_WM_STATE_MAXIMIZED_HORZ", *a.Maximized)
+	}
+}
+
+func (w *Window) setInitialState(state string, set bool) {
+	w.states[state] = set
+}
+
+// applyRuleGeometry places window according to geometry from rules,
+// relative to the head the window would be placed on otherwise
+func (w *Window) applyRuleGeometry(g xrect.Rect) {
+	e := w.GetFrameExtents()
+	frame := xrect.New(g.X(), g.Y(), g.Width()+e.Left+e.Right, g.Height()+e.Top+e.Bottom)
+	head, err := heads.GetHeadForRectStruts(frame)
+	if err != nil {
+		w.MoveResizeWinSize(true, g.X(), g.Y(), g.Width(), g.Height())
+		return
+	}
+	x, y, width, height := w.actions.Geometry.Resolve(head, frame)
+	w.MoveResize(true, x, y, width, height)
+}
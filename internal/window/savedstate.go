@@ -10,6 +10,7 @@ const (
 	StatePriorMaxVert state = iota
 	StatePriorMaxHorz
 	StatePriorFullscreen
+	StatePriorShade
 )
 
 type windowState struct {
@@ -32,6 +33,11 @@ func (w *Window) LoadWindowState(s state) {
 		f = ConfigY | ConfigHeight
 	} else if s == StatePriorMaxHorz {
 		f = ConfigX | ConfigWidth
+	} else if s == StatePriorShade {
+		// window could be moved or resized horizontally while shaded, so only height is restored
+		if cur, err := w.Geometry(); err == nil {
+			g = xrect.New(cur.X(), cur.Y(), cur.Width(), g.Height())
+		}
 	}
 
 	w.moveResizeInternal(false, g.X(), g.Y(), g.Width(), g.Height(), f)
@@ -0,0 +1,79 @@
+package window
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/xgbutil/xprop"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/rules"
+	"github.com/janbina/swm/internal/session"
+	"github.com/janbina/swm/internal/stack"
+	"github.com/janbina/swm/internal/util"
+)
+
+var savedStateNames = map[state]string{
+	StatePriorMaxVert:    "max-vert",
+	StatePriorMaxHorz:    "max-horz",
+	StatePriorFullscreen: "fullscreen",
+	StatePriorShade:      "shade",
+}
+
+// SessionState returns state of the window to be saved in session
+// Groups, focus and stacking order are not known to the window, so they are left empty
+func (w *Window) SessionState() *session.Window {
+	s := w.SessionIdentity()
+	if g, err := w.Geometry(); err == nil {
+		s.Geometry = util.NewGeometry(g)
+	}
+	switch w.layer {
+	case stack.LayerAbove:
+		s.Layer = rules.LayerAbove
+	case stack.LayerBelow:
+		s.Layer = rules.LayerBelow
+	default:
+		s.Layer = rules.LayerDefault
+	}
+	s.SavedGeometries = make(map[string]util.Geometry)
+	for st, ws := range w.savedStates {
+		if name, ok := savedStateNames[st]; ok && ws.geom != nil {
+			s.SavedGeometries[name] = util.NewGeometry(ws.geom)
+		}
+	}
+	return s
+}
+
+// SessionIdentity returns properties the window is matched by against saved session
+func (w *Window) SessionIdentity() *session.Window {
+	s := &session.Window{
+		Id:       w.win.Id,
+		Class:    w.class.Class,
+		Instance: w.class.Instance,
+	}
+	if role, err := xprop.PropValStr(xprop.GetProperty(w.win.X, w.win.Id, "WM_WINDOW_ROLE")); err == nil {
+		s.Role = role
+	}
+	if cmd, err := xprop.PropValStrs(xprop.GetProperty(w.win.X, w.win.Id, "WM_COMMAND")); err == nil {
+		s.Command = strings.Join(cmd, " ")
+	}
+	return s
+}
+
+// RestoreSessionLayer sets layer from session, it has to be called before initial states are applied
+func (w *Window) RestoreSessionLayer(s *session.Window) {
+	w.setInitialState("_NET_WM_STATE_ABOVE", s.Layer == rules.LayerAbove)
+	w.setInitialState("_NET_WM_STATE_BELOW", s.Layer == rules.LayerBelow)
+}
+
+// RestoreSessionGeometry restores frame geometry and geometries saved by maximized, fullscreen or shaded state
+// It has to be called after initial states are applied, as they change the geometry
+func (w *Window) RestoreSessionGeometry(s *session.Window) {
+	g := s.Geometry
+	if g.Width > 0 && g.Height > 0 {
+		w.moveResizeInternal(false, g.X, g.Y, g.Width, g.Height)
+	}
+	for st, name := range savedStateNames {
+		if sg, ok := s.SavedGeometries[name]; ok {
+			w.savedStates[st] = windowState{geom: xrect.New(sg.X, sg.Y, sg.Width, sg.Height)}
+		}
+	}
+}
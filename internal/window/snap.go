@@ -0,0 +1,196 @@
+package window
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/heads"
+)
+
+const (
+	// how close to the head edge the pointer has to be to tile the dragged window
+	edgeTileMargin = 2
+	// how close to the head corner the pointer has to be to tile the dragged window to a quarter
+	cornerTileSize = 100
+)
+
+// window showing where the dragged window will be tiled, shared by all windows as only one can be dragged at a time
+var tilePreview *xwindow.Window
+
+// snapMove returns position of frame with given geometry snapped to the closest edges within snap distance
+func (w *Window) snapMove(x, y, width, height int) (int, int) {
+	if config.SnapDistance <= 0 {
+		return x, y
+	}
+	xs, ys := w.snapEdges(x, y, width, height)
+	return snapInterval(x, width, xs), snapInterval(y, height, ys)
+}
+
+// snapResize snaps edges of frame which are being resized to the closest edges within snap distance
+func (w *Window) snapResize(x, y, width, height int, changeX, changeY, changeW, changeH bool) (int, int, int, int) {
+	if config.SnapDistance <= 0 {
+		return x, y, width, height
+	}
+	xs, ys := w.snapEdges(x, y, width, height)
+	if changeX {
+		if sx, ok := snap(x, xs); ok {
+			width += x - sx
+			x = sx
+		}
+	} else if changeW {
+		if sx, ok := snap(x+width, xs); ok {
+			width = sx - x
+		}
+	}
+	if changeY {
+		if sy, ok := snap(y, ys); ok {
+			height += y - sy
+			y = sy
+		}
+	} else if changeH {
+		if sy, ok := snap(y+height, ys); ok {
+			height = sy - y
+		}
+	}
+	return x, y, width, height
+}
+
+// snapEdges returns x positions of vertical and y positions of horizontal edges the frame can snap to
+// Only edges of heads, struts and other visible windows near the frame on the other axis are considered
+func (w *Window) snapEdges(x, y, width, height int) (xs, ys []int) {
+	rects := make([]xrect.Rect, 0)
+	rects = append(rects, heads.Heads...)
+	rects = append(rects, heads.HeadsStruts...)
+	rects = append(rects, w.visibleFrames()...)
+
+	d := config.SnapDistance
+	for _, r := range rects {
+		if r.Y() <= y+height+d && y <= r.Y()+r.Height()+d {
+			xs = append(xs, r.X(), r.X()+r.Width())
+		}
+		if r.X() <= x+width+d && x <= r.X()+r.Width()+d {
+			ys = append(ys, r.Y(), r.Y()+r.Height())
+		}
+	}
+	return xs, ys
+}
+
+// snapInterval returns new start of interval, so that the end closer to some edge is snapped to it
+func snapInterval(start, size int, edges []int) int {
+	s, okStart := snap(start, edges)
+	e, okEnd := snap(start+size, edges)
+	if okEnd && (!okStart || abs(e-start-size) < abs(s-start)) {
+		return e - size
+	}
+	return s
+}
+
+// snap returns edge closest to pos, if it is within snap distance
+func snap(pos int, edges []int) (int, bool) {
+	best, found := pos, false
+	for _, e := range edges {
+		if d := abs(e - pos); d <= config.SnapDistance && (!found || d < abs(best-pos)) {
+			best, found = e, true
+		}
+	}
+	return best, found
+}
+
+// edgeTileRect returns area the dragged window will be tiled to when dropped at given pointer position
+// Returns nil if edge tiling is disabled or pointer is not at the edge of a head
+func edgeTileRect(rx, ry int) xrect.Rect {
+	if !config.EdgeTiling {
+		return nil
+	}
+	head, err := heads.GetHeadForPointer(rx, ry)
+	if err != nil {
+		return nil
+	}
+	area, err := heads.GetHeadForRectStruts(head)
+	if err != nil {
+		return nil
+	}
+
+	atLeft := rx <= head.X()+edgeTileMargin
+	atRight := rx >= head.X()+head.Width()-1-edgeTileMargin
+	atTop := ry <= head.Y()+edgeTileMargin
+	atBottom := ry >= head.Y()+head.Height()-1-edgeTileMargin
+
+	// -1 for left/top half, 1 for right/bottom half, 0 for whole width/height
+	horz, vert := 0, 0
+	if atLeft || atRight {
+		horz = side(atLeft)
+		if ry < head.Y()+cornerTileSize {
+			vert = -1
+		} else if ry >= head.Y()+head.Height()-cornerTileSize {
+			vert = 1
+		}
+	}
+	if atTop || atBottom {
+		vert = side(atTop)
+		if rx < head.X()+cornerTileSize {
+			horz = -1
+		} else if rx >= head.X()+head.Width()-cornerTileSize {
+			horz = 1
+		}
+	}
+	if horz == 0 && vert == 0 {
+		return nil
+	}
+
+	x, width := halve(area.X(), area.Width(), horz)
+	y, height := halve(area.Y(), area.Height(), vert)
+	return xrect.New(x, y, width, height)
+}
+
+func side(first bool) int {
+	if first {
+		return -1
+	}
+	return 1
+}
+
+func halve(start, size, part int) (int, int) {
+	switch part {
+	case -1:
+		return start, size / 2
+	case 1:
+		return start + size/2, size - size/2
+	}
+	return start, size
+}
+
+func showTilePreview(X *xgbutil.XUtil, r xrect.Rect) {
+	if tilePreview == nil {
+		win, err := xwindow.Generate(X)
+		if err != nil {
+			return
+		}
+		err = win.CreateChecked(
+			X.RootWin(), r.X(), r.Y(), r.Width(), r.Height(),
+			xproto.CwBackPixel|xproto.CwOverrideRedirect,
+			config.InfoBoxBgColor, 1,
+		)
+		if err != nil {
+			return
+		}
+		tilePreview = win
+	}
+	tilePreview.MoveResize(r.X(), r.Y(), r.Width(), r.Height())
+	tilePreview.Map()
+}
+
+func hideTilePreview() {
+	if tilePreview != nil {
+		tilePreview.Unmap()
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,261 @@
+package window
+
+import (
+	"image"
+	"image/draw"
+	"log"
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/mousebind"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/decoration"
+	"github.com/janbina/swm/internal/util"
+)
+
+const (
+	// maximal delay between clicks of double click
+	doubleClickDelay = 400 * time.Millisecond
+	titlePadding     = 5
+)
+
+type titleButton int
+
+const (
+	buttonNone titleButton = iota
+	buttonClose
+	buttonMaximize
+	buttonMinimize
+)
+
+// buttons in order from the right edge of title bar
+var titleButtons = []titleButton{buttonClose, buttonMaximize, buttonMinimize}
+
+var titleButtonLabels = map[titleButton]string{
+	buttonClose:    "×",
+	buttonMaximize: "□",
+	buttonMinimize: "_",
+}
+
+// titleBar is a decoration at the top of the window showing its name and close, maximize and minimize buttons
+// Clicking it focuses and raises the window, dragging moves the window and double click toggles maximized state
+type titleBar struct {
+	w         *Window
+	win       *xwindow.Window
+	color     uint32
+	width     int
+	lastClick time.Time
+}
+
+// createTitleBar returns nil when the title bar window cannot be created
+func createTitleBar(w *Window) *titleBar {
+	X := w.win.X
+
+	win, err := xwindow.Create(X, w.parent.Id)
+	if err != nil {
+		log.Printf("Cannot create title bar: %s", err)
+		return nil
+	}
+
+	t := &titleBar{
+		w:     w,
+		win:   win,
+		color: config.BorderTop.ColorNormal,
+	}
+
+	mousebind.Drag(X, X.Dummy(), win.Id, "1", true, t.dragBegin, dragMoveStep(w), dragMoveEnd(w))
+
+	return t
+}
+
+func (t *titleBar) dragBegin(X *xgbutil.XUtil, rx, ry, ex, ey int) (bool, xproto.Cursor) {
+	if b := t.buttonAt(ex); b != buttonNone {
+		t.buttonPressed(b)
+		return false, 0
+	}
+
+	now := time.Now()
+	if now.Sub(t.lastClick) < doubleClickDelay {
+		t.lastClick = time.Time{}
+		t.w.Focus()
+		t.w.Raise()
+		t.w.MaximizeToggle()
+		return false, 0
+	}
+	t.lastClick = now
+
+	return dragMoveBegin(t.w)(X, rx, ry, ex, ey)
+}
+
+func (t *titleBar) buttonAt(x int) titleButton {
+	i := (t.width - x - 1) / config.TitleBarHeight
+	if x >= t.width || i >= len(titleButtons) {
+		return buttonNone
+	}
+	return titleButtons[i]
+}
+
+func (t *titleBar) buttonPressed(b titleButton) {
+	switch b {
+	case buttonClose:
+		t.w.Destroy()
+	case buttonMaximize:
+		t.w.Focus()
+		t.w.Raise()
+		t.w.MaximizeToggle()
+	case buttonMinimize:
+		t.w.Iconify()
+	}
+}
+
+// render draws window name and buttons to an image, which is then used as background of the title bar
+func (t *titleBar) render() {
+	h := config.TitleBarHeight
+	if t.win == nil || t.width <= 0 || h <= 0 {
+		return
+	}
+	X := t.win.X
+	textSize := float64(h) * 0.6
+
+	bar := util.CreateFilledImage(X, t.width, h, t.color)
+
+	if t.w.name != "" {
+		if text, err := util.CreateTextBox(X, t.w.name, textSize, 0, t.color, config.TitleBarTextColor); err == nil {
+			// too long name is cut before the buttons
+			area := image.Rect(titlePadding, 0, t.width-len(titleButtons)*h, h)
+			drawCentered(bar, text, area, false)
+			text.Destroy()
+		} else {
+			log.Printf("Cannot render window name: %s", err)
+		}
+	}
+
+	for i, b := range titleButtons {
+		area := image.Rect(t.width-(i+1)*h, 0, t.width-i*h, h)
+		if label, err := util.CreateTextBox(X, titleButtonLabels[b], textSize, 0, t.color, config.TitleBarTextColor); err == nil {
+			drawCentered(bar, label, area, true)
+			label.Destroy()
+		}
+	}
+
+	if err := util.SetBackgroundImage(t.win, bar); err != nil {
+		log.Printf("Cannot set title bar image: %s", err)
+	}
+}
+
+// drawCentered draws src to dst, vertically centered in area, and horizontally too if center is true
+func drawCentered(dst draw.Image, src image.Image, area image.Rectangle, center bool) {
+	b := src.Bounds()
+	x := area.Min.X
+	if center {
+		x += (area.Dx() - b.Dx()) / 2
+	}
+	y := area.Min.Y + (area.Dy()-b.Dy())/2
+	draw.Draw(dst, image.Rect(x, y, x+b.Dx(), y+b.Dy()).Intersect(area), src, b.Min, draw.Src)
+}
+
+func (t *titleBar) setColor(color uint32) {
+	t.color = color
+	t.render()
+}
+
+func (t *titleBar) ApplyRect(c *decoration.WinConfig, rect xrect.Rect, f int) xrect.Rect {
+	newRect := xrect.New(rect.Pieces())
+
+	if c.Fullscreen {
+		t.win.Unmap()
+		return newRect
+	}
+
+	h := config.TitleBarHeight
+	t.win.MROpt(f, rect.X(), rect.Y(), rect.Width(), h)
+	t.win.Map()
+
+	if rect.Width() != t.width {
+		t.width = rect.Width()
+		t.render()
+	}
+
+	newRect.YSet(newRect.Y() + h)
+	newRect.HeightSet(newRect.Height() - h)
+	return newRect
+}
+
+func (t *titleBar) WidthNeeded(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (t *titleBar) HeightNeeded(c *decoration.WinConfig) int {
+	return t.Top(c)
+}
+
+func (t *titleBar) Left(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (t *titleBar) Right(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (t *titleBar) Top(c *decoration.WinConfig) int {
+	if c.Fullscreen {
+		return 0
+	}
+	return config.TitleBarHeight
+}
+
+func (t *titleBar) Bottom(_ *decoration.WinConfig) int {
+	return 0
+}
+
+func (t *titleBar) Active() {
+	t.setColor(config.BorderTop.ColorActive)
+}
+
+func (t *titleBar) InActive() {
+	t.setColor(config.BorderTop.ColorNormal)
+}
+
+func (t *titleBar) Attention() {
+	t.setColor(config.BorderTop.ColorAttention)
+}
+
+func (t *titleBar) Destroy() {
+	mousebind.Detach(t.win.X, t.win.Id)
+	t.win.Destroy()
+}
+
+func (w *Window) HasTitleBar() bool {
+	return w.titleBar != nil
+}
+
+// SetTitleBar adds or removes title bar of the window, frame keeps its geometry
+func (w *Window) SetTitleBar(show bool) {
+	if show == w.HasTitleBar() {
+		return
+	}
+	if show {
+		if w.titleBar = createTitleBar(w); w.titleBar == nil {
+			return
+		}
+		w.decorations = append(w.decorations, w.titleBar)
+	} else {
+		w.decorations.Remove(w.titleBar)
+		w.titleBar.Destroy()
+		w.titleBar = nil
+	}
+	w.Redecorate()
+}
+
+func (w *Window) shouldHaveTitleBar() bool {
+	if w.actions.TitleBar != nil {
+		return *w.actions.TitleBar
+	}
+	if !w.shouldDecorate() {
+		return false
+	}
+	return config.TitleBar
+}
@@ -3,6 +3,7 @@ package window
 import (
 	"github.com/BurntSushi/xgbutil/ewmh"
 	"github.com/BurntSushi/xgbutil/icccm"
+	"github.com/janbina/swm/internal/events"
 )
 
 func (w *Window) GetActiveStates() []string {
@@ -10,15 +11,41 @@ func (w *Window) GetActiveStates() []string {
 }
 
 func (w *Window) AddStates(states ...string) {
+	for _, s := range states {
+		if !w.states[s] {
+			events.StateAddedEvent(w.win.Id, s)
+		}
+	}
 	w.states.SetAll(states)
 	ewmh.WmStateSet(w.win.X, w.win.Id, w.states.GetActive())
 }
 
 func (w *Window) RemoveStates(states ...string) {
+	for _, s := range states {
+		if w.states[s] {
+			events.StateRemovedEvent(w.win.Id, s)
+		}
+	}
 	w.states.UnSetAll(states)
 	ewmh.WmStateSet(w.win.X, w.win.Id, w.states.GetActive())
 }
 
+func (w *Window) HasState(state string) bool {
+	return w.states[state]
+}
+
+// SetSticky updates _NET_WM_STATE_STICKY, window is sticky when it is member of the sticky group
+func (w *Window) SetSticky(sticky bool) {
+	if w.states["_NET_WM_STATE_STICKY"] == sticky {
+		return
+	}
+	if sticky {
+		w.AddStates("_NET_WM_STATE_STICKY")
+	} else {
+		w.RemoveStates("_NET_WM_STATE_STICKY")
+	}
+}
+
 func (w *Window) SetIcccmState(state uint) error {
 	return icccm.WmStateSet(w.win.X, w.win.Id, &icccm.WmState{State: state})
 }
@@ -73,6 +73,48 @@ func GetWindowScreenGeometryStruts(id int) (xrect.Rect, error) {
 	return heads.GetHeadForRectStruts(winGeom)
 }
 
+// SendToHead moves window to another head, target is next, prev, or index or name of the head
+// Window keeps its geometry relative to the head, if warp is true, pointer follows the window
+func SendToHead(id int, target string, warp bool) error {
+	win, err := GetWindowById(id)
+	if err != nil {
+		return err
+	}
+	g, err := win.Geometry()
+	if err != nil {
+		return err
+	}
+	if len(heads.HeadsStruts) == 0 {
+		return fmt.Errorf("no heads")
+	}
+	from := heads.GetHeadIndexForRect(g)
+	to, err := heads.GetTargetHeadIndex(target, from)
+	if err != nil {
+		return err
+	}
+
+	if to != from {
+		win.MoveToHead(heads.HeadsStruts[from], heads.HeadsStruts[to])
+		if win.IsFocused() {
+			groupmanager.SetFocusedHead(heads.Id(to))
+		}
+		if group, ok := groupmanager.GetHeadGroup(heads.Id(to)); ok && !groupmanager.IsWinSticky(win.Id()) {
+			// in per-head mode window joins the group shown on the new head, so it doesn't move back with its group,
+			// sticky window is visible on every head, so it keeps its groups
+			applyChanges(groupmanager.SetGroupForWindow(win.Id(), group))
+		} else {
+			relayout()
+		}
+	}
+
+	if warp {
+		if g, err = win.Geometry(); err == nil {
+			util.WarpPointer(X, g.X()+g.Width()/2, g.Y()+g.Height()/2)
+		}
+	}
+	return nil
+}
+
 func GetWindowGeometry(id int) (xrect.Rect, error) {
 	win, err := GetWindowById(id)
 	if err != nil {
@@ -85,6 +127,8 @@ func CycleWin() {
 	cycleState--
 	if win, ok := focus.CyclingFocus(cycleState).(*window.Window); ok {
 		stack.TmpRaise(win)
+		// cycled iconified window is temporarily deiconified
+		relayout()
 	}
 }
 
@@ -92,6 +136,8 @@ func CycleWinRev() {
 	cycleState++
 	if win, ok := focus.CyclingFocus(cycleState).(*window.Window); ok {
 		stack.TmpRaise(win)
+		// cycled iconified window is temporarily deiconified
+		relayout()
 	}
 }
 
@@ -100,6 +146,7 @@ func CycleWinEnd() {
 	if win, ok := focus.CyclingEnded().(*window.Window); ok {
 		win.RemoveTmpDeiconified()
 		win.Raise()
+		relayout()
 	}
 }
 
@@ -121,6 +168,39 @@ func SetResizeDragShortcut(s string) error {
 	return nil
 }
 
+func SetTitleBar(id int, show bool) error {
+	return doOnWindow(id, func(win *window.Window) {
+		win.SetTitleBar(show)
+	})
+}
+
+func ToggleTitleBar(id int) error {
+	return doOnWindow(id, func(win *window.Window) {
+		win.SetTitleBar(!win.HasTitleBar())
+	})
+}
+
+func SetShaded(id int, shaded bool) error {
+	return doOnWindow(id, func(win *window.Window) {
+		if shaded {
+			win.Shade()
+		} else {
+			win.UnShade()
+		}
+	})
+}
+
+func ToggleShaded(id int) error {
+	return doOnWindow(id, (*window.Window).ShadeToggle)
+}
+
+// DecorationsChanged redecorates all windows, it should be called after border config changes
+func DecorationsChanged() {
+	for _, win := range managedWindows {
+		win.Redecorate()
+	}
+}
+
 func mouseShortcutsChanged() {
 	for _, win := range managedWindows {
 		win.SetupMouseEvents()
@@ -153,6 +233,22 @@ func BeginMouseResizeFromPointer() error {
 	return nil
 }
 
+func BeginKeyboardMove(id int) error {
+	win, err := GetWindowById(id)
+	if err != nil {
+		return err
+	}
+	return win.KeyboardMoveBegin()
+}
+
+func BeginKeyboardResize(id int) error {
+	win, err := GetWindowById(id)
+	if err != nil {
+		return err
+	}
+	return win.KeyboardResizeBegin()
+}
+
 // GROUPS
 
 func SetGroupForWindow(id int, group int) error {
@@ -197,6 +293,31 @@ func GetWindowGroups(id int) ([]uint, error) {
 	return groupmanager.GetWinGroups(win.Id()), nil
 }
 
+func stickWindow(win *window.Window) {
+	if !groupmanager.IsWinSticky(win.Id()) {
+		applyChanges(groupmanager.StickWindow(win.Id()))
+	}
+}
+
+func unstickWindow(win *window.Window) {
+	applyChanges(groupmanager.UnstickWindow(win.Id()))
+}
+
+func stickToggle(win *window.Window) {
+	if groupmanager.IsWinSticky(win.Id()) {
+		unstickWindow(win)
+	} else {
+		stickWindow(win)
+	}
+}
+
+// updateStickyStates keeps _NET_WM_STATE_STICKY of windows in sync with their membership in the sticky group
+func updateStickyStates() {
+	for id, win := range managedWindows {
+		win.SetSticky(groupmanager.IsWinSticky(id))
+	}
+}
+
 func setNumberOfDesktops(num int) {
 	changes := groupmanager.SetNumberOfGroups(num)
 	applyChanges(changes)
@@ -226,7 +347,42 @@ func ToggleGroupVisibility(group int) {
 func ShowGroupOnly(group int) {
 	changes := groupmanager.ShowGroupOnly(group)
 	applyChanges(changes)
-	focus.FocusLast()
+	if groupmanager.PerHead {
+		// other heads still show their groups, so focus should stay on this one
+		focus.FocusLastWithPreference(func(win xproto.Window) bool {
+			return groupmanager.IsWinInGroup(win, group)
+		})
+	} else {
+		focus.FocusLast()
+	}
+}
+
+// ShowGroupOnHead shows group on head given by its name or index in per-head mode,
+// if only is true, other groups on the head are hidden
+func ShowGroupOnHead(group int, head string, only bool) error {
+	changes, err := groupmanager.ShowGroupOnHead(group, head, only)
+	if err != nil {
+		return err
+	}
+	applyChanges(changes)
+	focus.FocusLastWithPreference(func(win xproto.Window) bool {
+		return groupmanager.IsWinInGroup(win, group)
+	})
+	return nil
+}
+
+// SwapHeads swaps groups of two heads in per-head mode
+func SwapHeads(a, b string) error {
+	changes, err := groupmanager.SwapHeads(a, b)
+	if err != nil {
+		return err
+	}
+	applyChanges(changes)
+	return nil
+}
+
+func SetGroupsPerHead(enabled bool) {
+	applyChanges(groupmanager.SetPerHead(enabled))
 }
 
 func ShowGroup(group int) {
@@ -249,10 +405,33 @@ func ShowGroupInfo(win *window.Window) {
 	win.ShowInfoBox(text, 3*time.Second)
 }
 
+// moveToHead moves window to head given by its id, if it is not there already
+func moveToHead(win *window.Window, head string) {
+	to, err := heads.GetHeadIndex(head)
+	if err != nil || to >= len(heads.HeadsStruts) {
+		return
+	}
+	g, err := win.Geometry()
+	if err != nil {
+		return
+	}
+	from := xrect.LargestOverlap(g, heads.Heads)
+	if from < 0 || from == to || from >= len(heads.HeadsStruts) {
+		return
+	}
+	win.MoveToHead(heads.HeadsStruts[from], heads.HeadsStruts[to])
+}
+
 func applyChanges(changes *groupmanager.Changes) {
 	if changes == nil {
 		return
 	}
+	updateStickyStates()
+	for w, head := range changes.Moves {
+		if win := managedWindows[w]; win != nil {
+			moveToHead(win, head)
+		}
+	}
 	for _, w := range changes.Invisible {
 		win := managedWindows[w]
 		if win == nil {
@@ -278,4 +457,6 @@ func applyChanges(changes *groupmanager.Changes) {
 	if len(wins) > 0 {
 		stack.RaiseMulti(wins)
 	}
+	relayout()
+	IgnoreEnterEvents()
 }
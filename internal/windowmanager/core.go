@@ -2,13 +2,14 @@ package windowmanager
 
 import (
 	"log"
+	"os"
+	"syscall"
 
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/keybind"
 	"github.com/BurntSushi/xgbutil/mousebind"
 	"github.com/BurntSushi/xgbutil/xevent"
-	"github.com/BurntSushi/xgbutil/xinerama"
 	"github.com/BurntSushi/xgbutil/xrect"
 	"github.com/BurntSushi/xgbutil/xwindow"
 	"github.com/janbina/swm/internal/cursors"
@@ -16,6 +17,7 @@ import (
 	"github.com/janbina/swm/internal/groupmanager"
 	"github.com/janbina/swm/internal/heads"
 	"github.com/janbina/swm/internal/stack"
+	"github.com/janbina/swm/internal/util"
 	"github.com/janbina/swm/internal/window"
 )
 
@@ -29,6 +31,14 @@ var (
 	strutWindows   map[xproto.Window]bool
 
 	cycleState int
+
+	// whether swm should exec itself after the event loop ends
+	restarting bool
+
+	// commands which have to be executed on the same goroutine as X event handlers
+	commandQueue = make(chan func())
+	// closed when the event loop ends, so no more commands are executed
+	stopped = make(chan struct{})
 )
 
 // Take wm ownership and initialize variables
@@ -42,6 +52,8 @@ func Initialize(x *xgbutil.XUtil, replace bool) error {
 	focus.Initialize(X)
 	stack.Initialize(X)
 	groupmanager.Initialize(X)
+	util.InitShape(X)
+	heads.Initialize(X)
 
 	if err = takeWmOwnership(X, replace); err != nil {
 		return err
@@ -69,6 +81,7 @@ func SetupRoot() error {
 		xproto.EventMaskStructureNotify,
 		xproto.EventMaskSubstructureRedirect,
 		xproto.EventMaskSubstructureNotify,
+		xproto.EventMaskEnterWindow,
 	); err != nil {
 		return err
 	}
@@ -76,19 +89,27 @@ func SetupRoot() error {
 	xevent.ConfigureRequestFun(configureRequestFun).Connect(X, Root.Id)
 	xevent.MapRequestFun(mapRequestFun).Connect(X, Root.Id)
 	xevent.ClientMessageFun(handleRootClientMessage).Connect(X, Root.Id)
+	setupRootEnterListener()
 	xevent.ConfigureNotifyFun(func(X *xgbutil.XUtil, e xevent.ConfigureNotifyEvent) {
 		log.Printf("Root geometry changed: %s", e)
 		_ = loadGeometriesAndHeads()
 	}).Connect(X, Root.Id)
+	heads.SelectChanges(X, func() {
+		_ = loadGeometriesAndHeads()
+	})
 
 	return nil
 }
 
+// ManageExistingClients manages windows which exist before swm starts,
+// their state is restored from saved session, if there is any
 func ManageExistingClients() error {
 	tree, err := xproto.QueryTree(X.Conn(), Root.Id).Reply()
 	if err != nil {
 		return err
 	}
+	loadSession()
+	defer finishSessionRestore()
 	for _, child := range tree.Children {
 		if child == X.Dummy() {
 			continue
@@ -98,7 +119,7 @@ func ManageExistingClients() error {
 		if err != nil {
 			continue
 		}
-		if attrs.MapState == xproto.MapStateUnmapped {
+		if attrs.MapState == xproto.MapStateUnmapped && !isInRestoredSession(child) {
 			continue
 		}
 
@@ -107,29 +128,197 @@ func ManageExistingClients() error {
 	return nil
 }
 
+// Run starts the main event loop
+// X events and queued commands (see Execute) are processed one at a time,
+// so they never run concurrently and can safely work with wm state
 func Run() {
-	xevent.Main(X)
+	defer close(stopped)
+	pingBefore, pingAfter, pingQuit := xevent.MainPing(X)
+	for {
+		select {
+		case <-pingBefore:
+			// wait for event handlers to finish
+			<-pingAfter
+		case f := <-commandQueue:
+			f()
+			IgnoreEnterEvents()
+			if xevent.Quitting(X) {
+				return
+			}
+		case <-pingQuit:
+			return
+		}
+	}
+}
+
+// RunWithCommands runs fun on separate goroutine and executes queued commands until it returns
+// It is meant to be used before the main event loop is started - swmrc sends commands
+// to swm and waits for the response, so they have to be processed while it is running
+func RunWithCommands(fun func()) {
+	done := make(chan struct{})
+	go func() {
+		fun()
+		close(done)
+	}()
+	for {
+		select {
+		case f := <-commandQueue:
+			f()
+		case <-done:
+			return
+		}
+	}
+}
+
+// Execute queues fun to be executed on the main event loop and waits for it to finish,
+// returns false when the event loop has already ended and fun was not executed
+// Must not be called from the event loop itself
+func Execute(fun func()) bool {
+	done := make(chan struct{})
+	select {
+	case commandQueue <- func() {
+		fun()
+		close(done)
+	}:
+		<-done
+		return true
+	case <-stopped:
+		return false
+	}
+}
+
+// Restart shuts down the event loop like Shutdown, Run returns and ExecRestart should be called afterwards
+func Restart() {
+	restarting = true
+	Shutdown()
+}
+
+func IsRestarting() bool {
+	return restarting
+}
+
+// ExecRestart releases all windows and replaces current process with new swm process,
+// which restores the saved session. It returns only if the exec fails.
+func ExecRestart() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	for _, win := range managedWindows {
+		win.Unwind()
+	}
+	// make sure X server processed all requests before the connection is closed by exec
+	_, _ = xproto.GetInputFocus(X.Conn()).Reply()
+	log.Printf("Restarting %s", exe)
+	return syscall.Exec(exe, os.Args, os.Environ())
 }
 
 func Shutdown() {
+	if err := SaveSession(); err != nil {
+		log.Printf("Cannot save session: %s", err)
+	}
 	xevent.Quit(X)
 }
 
 func loadGeometriesAndHeads() error {
 	var err error
-	RootGeometry, err = Root.Geometry()
+	rootG, err := Root.Geometry()
 	if err != nil {
 		return err
 	}
+
+	list, err := heads.Query(X)
+	if err != nil || len(list) == 0 {
+		list = []heads.Head{{Rect: rootG, Primary: true}}
+	}
+
+	// randr sends several notifications for a single change
+	if RootGeometry != nil && rectEquals(rootG, RootGeometry) && !headsChanged(list) {
+		return nil
+	}
+
+	RootGeometry = rootG
 	setDesktopGeometry()
 	setDesktopViewport()
 
-	heads.Heads, err = xinerama.PhysicalHeads(X)
-	if err != nil || len(heads.Heads) == 0 {
-		heads.Heads = xinerama.Heads{RootGeometry}
+	moves := headMoves(list)
+	heads.Set(list)
+	applyStruts(moves)
+
+	return nil
+}
+
+// headsChanged returns whether list differs from current heads
+func headsChanged(list []heads.Head) bool {
+	if len(list) != len(heads.Heads) {
+		return true
+	}
+	for i, head := range list {
+		if head.Name != heads.Names[i] || !rectEquals(head.Rect, heads.Heads[i]) || head.Primary != (i == heads.Primary) {
+			return true
+		}
+	}
+	return false
+}
+
+// headMoves finds for each window the head it should move to after heads change to list,
+// windows stay on the output with the same name, windows from disconnected outputs go to the primary one
+// and return to their original output once it is connected again
+func headMoves(list []heads.Head) map[xproto.Window]window.HeadMove {
+	if len(heads.Heads) == 0 {
+		return nil
+	}
+	primary := list[0].Rect
+	for _, head := range list {
+		if head.Primary {
+			primary = head.Rect
+		}
+	}
+	findHead := func(name string) xrect.Rect {
+		for _, head := range list {
+			if name != "" && head.Name == name {
+				return head.Rect
+			}
+		}
+		return nil
 	}
 
-	applyStruts()
+	moves := make(map[xproto.Window]window.HeadMove)
+	for id, win := range managedWindows {
+		g, err := win.Geometry()
+		if err != nil {
+			continue
+		}
+		i := xrect.LargestOverlap(g, heads.Heads)
+		if i < 0 {
+			continue
+		}
+		from, name := heads.Heads[i], heads.Names[i]
 
-	return nil
+		if last := win.LastHead(); last != "" {
+			if _, connected := heads.GetHeadByName(last); !connected {
+				if to := findHead(last); to != nil {
+					moves[id] = window.HeadMove{From: from, To: to, Restore: true}
+					continue
+				}
+			}
+		}
+
+		if to := findHead(name); to != nil {
+			if !rectEquals(from, to) {
+				moves[id] = window.HeadMove{From: from, To: to}
+			}
+		} else if name == "" && i < len(list) {
+			if !rectEquals(from, list[i].Rect) {
+				moves[id] = window.HeadMove{From: from, To: list[i].Rect}
+			}
+		} else {
+			moves[id] = window.HeadMove{From: from, To: primary, Lost: name}
+		}
+	}
+	return moves
+}
+
+func rectEquals(a, b xrect.Rect) bool {
+	return a.X() == b.X() && a.Y() == b.Y() && a.Width() == b.Width() && a.Height() == b.Height()
 }
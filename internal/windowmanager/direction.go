@@ -0,0 +1,331 @@
+package windowmanager
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/groupmanager"
+	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/window"
+)
+
+type Direction int
+
+const (
+	West Direction = iota
+	East
+	North
+	South
+)
+
+func ParseDirection(s string) (Direction, error) {
+	switch s {
+	case "west", "w":
+		return West, nil
+	case "east", "e":
+		return East, nil
+	case "north", "n":
+		return North, nil
+	case "south", "s":
+		return South, nil
+	}
+	return 0, fmt.Errorf("invalid direction: %s", s)
+}
+
+// FocusDirection focuses the nearest visible window in given direction from the active window
+// Only windows on the same head are considered, unless wrap is true - then windows on all heads are considered,
+// and if there is none in given direction, it wraps around to the farthest window on the other side
+func FocusDirection(dir Direction, wrap bool) error {
+	active, err := GetWindowById(0)
+	if err != nil {
+		return err
+	}
+	g, err := active.Geometry()
+	if err != nil {
+		return err
+	}
+
+	candidates := getFocusCandidates(active, g, wrap)
+
+	target := findNearest(g, candidates, dir)
+	if target == nil && wrap {
+		target = findFarthest(g, candidates, opposite(dir))
+	}
+	if target == nil {
+		return fmt.Errorf("no window in that direction")
+	}
+
+	target.Focus()
+	target.Raise()
+	return nil
+}
+
+func getFocusCandidates(active *window.Window, g xrect.Rect, allHeads bool) []*window.Window {
+	head, _ := heads.GetHeadForRect(g)
+
+	wins := make([]*window.Window, 0)
+	for _, win := range managedWindows {
+		if win == active || !isFocusCandidate(win) {
+			continue
+		}
+		if !allHeads && head != nil {
+			if wg, err := win.Geometry(); err != nil || !sameRect(head, headFor(wg)) {
+				continue
+			}
+		}
+		wins = append(wins, win)
+	}
+	// ties in distance are resolved by manage order, not by random map iteration
+	sortByManageOrder(wins)
+	return wins
+}
+
+// getSwapCandidates returns visible windows on all heads which can be swapped with win, sorted by manage order
+func getSwapCandidates(win *window.Window) []*window.Window {
+	wins := make([]*window.Window, 0)
+	for _, w := range managedWindows {
+		if w != win && isVisibleNormal(w) && w.IsTileable() {
+			wins = append(wins, w)
+		}
+	}
+	sortByManageOrder(wins)
+	return wins
+}
+
+func isFocusCandidate(win *window.Window) bool {
+	return isVisibleNormal(win) && (win.CanFocus() || win.ShouldSendFocusNotify())
+}
+
+// visibleFrames returns frame geometries of visible windows other than except, desktops and docks are skipped
+func visibleFrames(except xproto.Window) []xrect.Rect {
+	frames := make([]xrect.Rect, 0)
+	for id, win := range managedWindows {
+		if id == except || !isVisibleNormal(win) {
+			continue
+		}
+		if g, err := win.Geometry(); err == nil {
+			frames = append(frames, g)
+		}
+	}
+	return frames
+}
+
+// isVisibleNormal returns whether window is mapped in visible group and is not desktop or dock
+func isVisibleNormal(win *window.Window) bool {
+	return win.IsFocusable() &&
+		groupmanager.IsWinGroupVisible(win.Id()) &&
+		!win.HasType("_NET_WM_WINDOW_TYPE_DESKTOP", "_NET_WM_WINDOW_TYPE_DOCK")
+}
+
+// SnapWindow moves window in given direction until its frame touches the nearest edge of other visible window,
+// head or strut. Window stays where it is if there is no such edge.
+func SnapWindow(id int, dir Direction) error {
+	win, err := GetWindowById(id)
+	if err != nil {
+		return err
+	}
+	g, err := win.Geometry()
+	if err != nil {
+		return err
+	}
+
+	// edges the window can snap to, together with their extent on the perpendicular axis
+	edges := make([]edge, 0)
+	for _, other := range managedWindows {
+		if other == win || !isVisibleNormal(other) {
+			continue
+		}
+		if og, err := other.Geometry(); err == nil {
+			edges = append(edges, outerEdge(og, opposite(dir)))
+		}
+	}
+	for _, head := range append(heads.Heads, heads.HeadsStruts...) {
+		edges = append(edges, outerEdge(head, dir))
+	}
+
+	x, y := g.X(), g.Y()
+	nearest := math.MaxInt64
+	for _, e := range edges {
+		if e.end <= perpendicularStart(g, dir) || e.start >= perpendicularEnd(g, dir) {
+			// edge is not in the way of the window
+			continue
+		}
+		var d int
+		switch dir {
+		case West:
+			d = g.X() - e.pos
+		case East:
+			d = e.pos - (g.X() + g.Width())
+		case North:
+			d = g.Y() - e.pos
+		case South:
+			d = e.pos - (g.Y() + g.Height())
+		}
+		if d <= 0 || d >= nearest {
+			continue
+		}
+		nearest = d
+		switch dir {
+		case West:
+			x = e.pos
+		case East:
+			x = e.pos - g.Width()
+		case North:
+			y = e.pos
+		case South:
+			y = e.pos - g.Height()
+		}
+	}
+
+	win.Move(x, y)
+	return nil
+}
+
+// SwapWindow exchanges geometry of window with its nearest neighbour in given direction
+// Their order is exchanged as well, so they stay swapped in tiling layouts
+func SwapWindow(id int, dir Direction) error {
+	win, err := GetWindowById(id)
+	if err != nil {
+		return err
+	}
+	g, err := win.Geometry()
+	if err != nil {
+		return err
+	}
+	other := findNearest(g, getSwapCandidates(win), dir)
+	if other == nil {
+		return fmt.Errorf("no window in that direction")
+	}
+	og, err := other.Geometry()
+	if err != nil {
+		return err
+	}
+
+	win.MoveResize(true, og.X(), og.Y(), og.Width(), og.Height())
+	other.MoveResize(true, g.X(), g.Y(), g.Width(), g.Height())
+	manageOrder[win.Id()], manageOrder[other.Id()] = manageOrder[other.Id()], manageOrder[win.Id()]
+	relayout()
+	return nil
+}
+
+// edge is a line at position pos on one axis, spanning from start to end on the other axis
+type edge struct {
+	pos, start, end int
+}
+
+// outerEdge returns edge of rect on given side
+func outerEdge(r xrect.Rect, side Direction) edge {
+	switch side {
+	case West:
+		return edge{r.X(), r.Y(), r.Y() + r.Height()}
+	case East:
+		return edge{r.X() + r.Width(), r.Y(), r.Y() + r.Height()}
+	case North:
+		return edge{r.Y(), r.X(), r.X() + r.Width()}
+	default:
+		return edge{r.Y() + r.Height(), r.X(), r.X() + r.Width()}
+	}
+}
+
+func perpendicularStart(r xrect.Rect, dir Direction) int {
+	if dir == West || dir == East {
+		return r.Y()
+	}
+	return r.X()
+}
+
+func perpendicularEnd(r xrect.Rect, dir Direction) int {
+	if dir == West || dir == East {
+		return r.Y() + r.Height()
+	}
+	return r.X() + r.Width()
+}
+
+// findNearest returns window closest to rect in given direction, compared by their centers
+// Distance on the perpendicular axis counts twice, so windows in line with rect are preferred
+func findNearest(rect xrect.Rect, wins []*window.Window, dir Direction) *window.Window {
+	var nearest *window.Window
+	min := math.MaxInt64
+	for _, win := range wins {
+		g, err := win.Geometry()
+		if err != nil {
+			continue
+		}
+		primary, perpendicular := centerDistance(rect, g, dir)
+		if primary <= 0 {
+			continue
+		}
+		if d := primary + 2*perpendicular; d < min {
+			min = d
+			nearest = win
+		}
+	}
+	return nearest
+}
+
+// findFarthest returns window farthest from rect in given direction
+func findFarthest(rect xrect.Rect, wins []*window.Window, dir Direction) *window.Window {
+	var farthest *window.Window
+	max := math.MinInt64
+	for _, win := range wins {
+		g, err := win.Geometry()
+		if err != nil {
+			continue
+		}
+		primary, perpendicular := centerDistance(rect, g, dir)
+		if d := primary - 2*perpendicular; d > max {
+			max = d
+			farthest = win
+		}
+	}
+	return farthest
+}
+
+// centerDistance returns distance of centers of two rects along the direction (negative if b lies in the other
+// direction) and absolute distance on the perpendicular axis
+func centerDistance(a, b xrect.Rect, dir Direction) (int, int) {
+	ax, ay := a.X()+a.Width()/2, a.Y()+a.Height()/2
+	bx, by := b.X()+b.Width()/2, b.Y()+b.Height()/2
+	switch dir {
+	case West:
+		return ax - bx, abs(ay - by)
+	case East:
+		return bx - ax, abs(ay - by)
+	case North:
+		return ay - by, abs(ax - bx)
+	default:
+		return by - ay, abs(ax - bx)
+	}
+}
+
+func opposite(dir Direction) Direction {
+	switch dir {
+	case West:
+		return East
+	case East:
+		return West
+	case North:
+		return South
+	default:
+		return North
+	}
+}
+
+func headFor(rect xrect.Rect) xrect.Rect {
+	head, _ := heads.GetHeadForRect(rect)
+	return head
+}
+
+func sameRect(a, b xrect.Rect) bool {
+	return a != nil && b != nil &&
+		a.X() == b.X() && a.Y() == b.Y() && a.Width() == b.Width() && a.Height() == b.Height()
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
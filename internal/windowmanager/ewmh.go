@@ -100,6 +100,8 @@ var ewmhSupported = []string{
 	"_NET_WM_STATE_SKIP_TASKBAR",
 	"_NET_WM_STATE_SKIP_PAGER",
 	"_NET_WM_STATE_HIDDEN",
+	"_NET_WM_STATE_SHADED",
+	"_NET_WM_STATE_STICKY",
 	"_NET_WM_STATE_FULLSCREEN",
 	"_NET_WM_STATE_ABOVE",
 	"_NET_WM_STATE_BELOW",
@@ -109,6 +111,8 @@ var ewmhSupported = []string{
 	"_NET_WM_ACTION_MOVE",
 	"_NET_WM_ACTION_RESIZE",
 	"_NET_WM_ACTION_MINIMIZE",
+	"_NET_WM_ACTION_SHADE",
+	"_NET_WM_ACTION_STICK",
 	"_NET_WM_ACTION_MAXIMIZE_HORZ",
 	"_NET_WM_ACTION_MAXIMIZE_VERT",
 	"_NET_WM_ACTION_FULLSCREEN",
@@ -126,8 +130,8 @@ var windowAllowedActions = []string{
 	"_NET_WM_ACTION_MOVE",
 	"_NET_WM_ACTION_RESIZE",
 	"_NET_WM_ACTION_MINIMIZE",
-	//"_NET_WM_ACTION_SHADE",
-	//"_NET_WM_ACTION_STICK",
+	"_NET_WM_ACTION_SHADE",
+	"_NET_WM_ACTION_STICK",
 	"_NET_WM_ACTION_MAXIMIZE_HORZ",
 	"_NET_WM_ACTION_MAXIMIZE_VERT",
 	"_NET_WM_ACTION_FULLSCREEN",
@@ -0,0 +1,73 @@
+package windowmanager
+
+import (
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/janbina/swm/internal/config"
+	"github.com/janbina/swm/internal/focus"
+	"github.com/janbina/swm/internal/window"
+)
+
+// pending raise of window focused by pointer
+var autoRaiseTimer *time.Timer
+
+// IgnoreEnterEvents makes enter events caused by changes made so far ignored,
+// so windows mapped, unmapped or restacked under the pointer don't steal focus
+func IgnoreEnterEvents() {
+	if config.FocusModel != config.FocusModelClick {
+		focus.IgnoreEnterEvents()
+	}
+}
+
+func setupRootEnterListener() {
+	xevent.EnterNotifyFun(func(X *xgbutil.XUtil, e xevent.EnterNotifyEvent) {
+		// pointer moved from a window to the root window
+		if config.FocusModel == config.FocusModelStrictMouse &&
+			e.Detail == xproto.NotifyDetailInferior && focus.AcceptEnter(e.Mode, e.Sequence) {
+			stopAutoRaise()
+			focus.ClearFocus()
+		}
+	}).Connect(X, Root.Id)
+}
+
+func windowEntered(win *window.Window) {
+	if config.FocusModel == config.FocusModelClick {
+		return
+	}
+	if !win.IsFocused() {
+		win.Focus()
+	}
+	scheduleAutoRaise(win)
+}
+
+// scheduleAutoRaise raises window after configured delay, if it is still focused then
+func scheduleAutoRaise(win *window.Window) {
+	stopAutoRaise()
+	if !config.AutoRaise {
+		return
+	}
+	id := win.Id()
+	raise := func() {
+		if w := managedWindows[id]; w != nil && w.IsFocused() {
+			w.Raise()
+			IgnoreEnterEvents()
+		}
+	}
+	if config.AutoRaiseDelay == 0 {
+		raise()
+		return
+	}
+	autoRaiseTimer = time.AfterFunc(time.Duration(config.AutoRaiseDelay)*time.Millisecond, func() {
+		Execute(raise)
+	})
+}
+
+func stopAutoRaise() {
+	if autoRaiseTimer != nil {
+		autoRaiseTimer.Stop()
+		autoRaiseTimer = nil
+	}
+}
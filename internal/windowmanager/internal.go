@@ -3,14 +3,17 @@ package windowmanager
 import (
 	"log"
 
+	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/ewmh"
 	"github.com/BurntSushi/xgbutil/xevent"
 	"github.com/BurntSushi/xgbutil/xinerama"
 	"github.com/BurntSushi/xgbutil/xrect"
 	"github.com/BurntSushi/xgbutil/xwindow"
+	"github.com/janbina/swm/internal/events"
 	"github.com/janbina/swm/internal/groupmanager"
 	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/window"
 )
 
 // Root window configure request
@@ -36,8 +39,10 @@ func mapRequestFun(_ *xgbutil.XUtil, e xevent.MapRequestEvent) {
 	manageWindow(e.Window)
 }
 
-func applyStruts() {
-	rootG := xwindow.RootGeometry(X)
+// applyStruts recomputes heads with struts applied,
+// windows are moved to other heads according to moves, which may be nil
+func applyStruts(moves map[xproto.Window]window.HeadMove) {
+	rootG := RootGeometry
 	wh := make(xinerama.Heads, len(heads.Heads)+1)
 	wh[0] = xrect.New(rootG.Pieces())
 	for i, head := range heads.Heads {
@@ -63,7 +68,10 @@ func applyStruts() {
 
 	setWorkArea(groupmanager.GetNumGroups())
 
-	for _, win := range managedWindows {
-		win.RootGeometryChanged()
+	for id, win := range managedWindows {
+		win.RootGeometryChanged(moves[id])
 	}
+	relayout()
+
+	events.HeadsChangedEvent()
 }
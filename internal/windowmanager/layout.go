@@ -0,0 +1,99 @@
+package windowmanager
+
+import (
+	"sort"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/groupmanager"
+	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/layout"
+	"github.com/janbina/swm/internal/window"
+)
+
+var (
+	// order in which windows were managed, tiled windows are arranged in this order
+	manageOrder   = map[xproto.Window]int{}
+	manageCounter = 0
+)
+
+func SetGroupLayout(group int, l string) error {
+	if err := groupmanager.SetGroupLayout(group, l); err != nil {
+		return err
+	}
+	relayout()
+	return nil
+}
+
+func SetGroupMasterCount(group int, count int, relative bool) error {
+	if err := groupmanager.SetGroupMasterCount(group, count, relative); err != nil {
+		return err
+	}
+	relayout()
+	return nil
+}
+
+func SetGroupMasterRatio(group int, ratio float64, relative bool) error {
+	if err := groupmanager.SetGroupMasterRatio(group, ratio, relative); err != nil {
+		return err
+	}
+	relayout()
+	return nil
+}
+
+// relayout arranges windows of all visible groups which have tiling layout
+// Windows are arranged separately on each head, based on which head they are on now,
+// window in more visible tiled groups (e.g. sticky one) is arranged only by the first of them
+func relayout() {
+	if len(heads.HeadsStruts) == 0 {
+		return
+	}
+	arranged := make(map[xproto.Window]bool)
+	for _, g := range groupmanager.GetVisibleGroups() {
+		l, masterCount, masterRatio := groupmanager.GetGroupLayout(int(g))
+		if l == layout.Floating {
+			continue
+		}
+
+		winsOnHeads := make([][]*window.Window, len(heads.HeadsStruts))
+		for _, win := range getTileableWindows(int(g)) {
+			if arranged[win.Id()] {
+				continue
+			}
+			arranged[win.Id()] = true
+			i := 0
+			if geom, err := win.Geometry(); err == nil {
+				if i = xrect.LargestOverlap(geom, heads.HeadsStruts); i < 0 {
+					i = 0
+				}
+			}
+			winsOnHeads[i] = append(winsOnHeads[i], win)
+		}
+
+		for i, wins := range winsOnHeads {
+			rects := layout.Arrange(l, heads.HeadsStruts[i], len(wins), masterCount, masterRatio)
+			for j, win := range wins {
+				r := rects[j]
+				win.MoveResize(true, r.X(), r.Y(), r.Width(), r.Height())
+			}
+		}
+	}
+}
+
+func getTileableWindows(group int) []*window.Window {
+	wins := make([]*window.Window, 0)
+	for _, id := range groupmanager.GetGroupWindows(group) {
+		if win := managedWindows[id]; win != nil && win.IsTileable() {
+			wins = append(wins, win)
+		}
+	}
+	sortByManageOrder(wins)
+	return wins
+}
+
+// sortByManageOrder sorts windows in the order they were managed (or swapped)
+func sortByManageOrder(wins []*window.Window) {
+	sort.Slice(wins, func(i, j int) bool {
+		return manageOrder[wins[i].Id()] < manageOrder[wins[j].Id()]
+	})
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/ewmh"
 	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/janbina/swm/internal/events"
 	"github.com/janbina/swm/internal/focus"
 	"github.com/janbina/swm/internal/groupmanager"
+	"github.com/janbina/swm/internal/heads"
 	"github.com/janbina/swm/internal/window"
 )
 
@@ -26,7 +29,9 @@ func manageWindow(w xproto.Window) {
 		return
 	}
 
-	win := window.New(X, w)
+	win := window.New(X, w, func() []xrect.Rect {
+		return visibleFrames(w)
+	})
 
 	if win == nil {
 		log.Printf("Cannot manage window id %d", w)
@@ -34,13 +39,28 @@ func manageWindow(w xproto.Window) {
 	}
 
 	managedWindows[w] = win
+	manageOrder[w] = manageCounter
+	manageCounter++
 	groupmanager.AddWindow(w)
+	saved := matchSession(win)
+	if saved != nil {
+		restoreSessionGroups(w, saved)
+		win.RestoreSessionLayer(saved)
+	} else if g, ok := win.InitialGroup(); ok {
+		// window is not mapped yet, so there are no visibility changes to apply
+		_ = groupmanager.SetGroupForWindow(w, g)
+	}
+	if win.HasState("_NET_WM_STATE_STICKY") {
+		// the same as above, window asks to be sticky from the start
+		_ = groupmanager.StickWindow(w)
+	}
+	win.SetSticky(groupmanager.IsWinSticky(w))
 
 	xproto.ChangeSaveSet(X.Conn(), xproto.SetModeInsert, w)
 
 	if s, _ := ewmh.WmStrutPartialGet(X, w); s != nil {
 		strutWindows[w] = true
-		applyStruts()
+		applyStruts(nil)
 	}
 
 	updateClientList()
@@ -49,16 +69,29 @@ func manageWindow(w xproto.Window) {
 	for _, s := range win.GetActiveStates() {
 		updateWinState(win, ewmh.StateAdd, s)
 	}
+	if saved != nil {
+		win.RestoreSessionGeometry(saved)
+	} else if head, ok := groupmanager.GetWinHead(w); ok {
+		// window belongs to group shown on another head
+		moveToHead(win, head)
+	}
 
 	setWmAllowedActions(w)
 
+	events.WindowManagedEvent(w)
+
 	if !win.IsIconified() && !win.IsHidden() && groupmanager.IsWinGroupVisible(w) {
 		win.Map()
-		win.Focus()
+		if win.FocusOnMap() {
+			win.Focus()
+		}
 		win.Raise()
 	} else {
 		win.Unmap()
 	}
+
+	relayout()
+	IgnoreEnterEvents()
 }
 
 func unmanageWindow(w xproto.Window) {
@@ -73,11 +106,15 @@ func unmanageWindow(w xproto.Window) {
 	xproto.ChangeSaveSet(X.Conn(), xproto.SetModeDelete, w)
 	focus.FocusLast()
 	delete(managedWindows, w)
+	delete(manageOrder, w)
 	updateClientList()
+	events.WindowUnmanagedEvent(w)
 	if strutWindows[w] {
 		delete(strutWindows, w)
-		applyStruts()
+		applyStruts(nil)
 	}
+	relayout()
+	IgnoreEnterEvents()
 }
 
 func setupListeners(w xproto.Window, win *window.Window) {
@@ -87,9 +124,14 @@ func setupListeners(w xproto.Window, win *window.Window) {
 		xproto.EventMaskStructureNotify,
 		xproto.EventMaskEnterWindow,
 		xproto.EventMaskFocusChange,
+		xproto.EventMaskPropertyChange,
 	)
 
-	win.SetupFocusListeners()
+	win.SetupFocusListeners(windowFocused, func(*window.Window) {
+		// iconified window doesn't take part in tiling
+		relayout()
+	})
+	win.SetupEnterListener(windowEntered)
 
 	xevent.ClientMessageFun(handleWindowClientMessage).Connect(X, w)
 
@@ -106,3 +148,10 @@ func setupListeners(w xproto.Window, win *window.Window) {
 		win.ConfigureRequest(e)
 	}).Connect(X, w)
 }
+
+// windowFocused remembers head of the focused window, in per-head mode current group is the one shown there
+func windowFocused(win *window.Window) {
+	if g, err := win.Geometry(); err == nil {
+		groupmanager.SetFocusedHead(heads.GetHeadIdForRect(g))
+	}
+}
@@ -138,7 +138,9 @@ func announce(X *xgbutil.XUtil) error {
 
 func disown(X *xgbutil.XUtil, _ xevent.SelectionClearEvent) {
 	log.Println("Exiting, will be replaced by another wm.")
-	xevent.Quit(X)
+	// new wm waits until our selection window is destroyed (see waitForWmShutdown),
+	// which happens after the session is saved, when we close the connection
+	Shutdown()
 }
 
 func currentTime(X *xgbutil.XUtil) (xproto.Timestamp, error) {
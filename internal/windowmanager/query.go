@@ -0,0 +1,149 @@
+package windowmanager
+
+import (
+	"sort"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/janbina/swm/internal/focus"
+	"github.com/janbina/swm/internal/groupmanager"
+	"github.com/janbina/swm/internal/heads"
+	"github.com/janbina/swm/internal/stack"
+	"github.com/janbina/swm/internal/util"
+	"github.com/janbina/swm/internal/window"
+)
+
+type WindowInfo struct {
+	Id       xproto.Window `json:"id"`
+	Name     string        `json:"name"`
+	Geometry util.Geometry `json:"geometry"`
+	Groups   []uint        `json:"groups"`
+	Layer    string        `json:"layer"`
+	States   []string      `json:"states"`
+	Focused  bool          `json:"focused"`
+	// position in focus history, 0 is the most recently focused window, -1 if window cannot be focused
+	FocusOrder int `json:"focus_order"`
+	// position in stacking order, 0 is the bottom-most window, -1 if window is not stacked yet
+	StackOrder int `json:"stack_order"`
+}
+
+type GroupInfo struct {
+	Id      uint            `json:"id"`
+	Name    string          `json:"name"`
+	Visible bool            `json:"visible"`
+	Current bool            `json:"current"`
+	Layout  string          `json:"layout"`
+	Windows []xproto.Window `json:"windows"`
+}
+
+type HeadInfo struct {
+	Index          int           `json:"index"`
+	Name           string        `json:"name"`
+	Primary        bool          `json:"primary"`
+	Geometry       util.Geometry `json:"geometry"`
+	GeometryStruts util.Geometry `json:"geometry_struts"`
+}
+
+var layerNames = map[int]string{
+	stack.LayerDesktop:    "desktop",
+	stack.LayerBelow:      "below",
+	stack.LayerDefault:    "default",
+	stack.LayerAbove:      "above",
+	stack.LayerDock:       "dock",
+	stack.LayerFullscreen: "fullscreen",
+}
+
+func QueryWindows() []*WindowInfo {
+	focusOrder := indexMap(focus.GetFocusOrder())
+	stackOrder := indexMap(stack.GetStackingOrder())
+
+	infos := make([]*WindowInfo, 0, len(managedWindows))
+	for _, win := range managedWindows {
+		infos = append(infos, getWindowInfo(win, focusOrder, stackOrder))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Id < infos[j].Id
+	})
+	return infos
+}
+
+func QueryWindow(id int) (*WindowInfo, error) {
+	win, err := GetWindowById(id)
+	if err != nil {
+		return nil, err
+	}
+	focusOrder := indexMap(focus.GetFocusOrder())
+	stackOrder := indexMap(stack.GetStackingOrder())
+	return getWindowInfo(win, focusOrder, stackOrder), nil
+}
+
+func QueryGroups() []*GroupInfo {
+	num := groupmanager.GetNumGroups()
+	ids := make([]int, 0, num+1)
+	for i := 0; i < num; i++ {
+		ids = append(ids, i)
+	}
+	ids = append(ids, groupmanager.StickyGroupID)
+
+	current := groupmanager.GetCurrentGroup()
+	infos := make([]*GroupInfo, len(ids))
+	for i, id := range ids {
+		l, _, _ := groupmanager.GetGroupLayout(id)
+		infos[i] = &GroupInfo{
+			Id:      uint(id),
+			Name:    groupmanager.GetGroupName(id),
+			Visible: groupmanager.IsGroupVisible(id),
+			Current: id == current,
+			Layout:  l,
+			Windows: groupmanager.GetGroupWindows(id),
+		}
+	}
+	return infos
+}
+
+func QueryHeads() []*HeadInfo {
+	infos := make([]*HeadInfo, len(heads.Heads))
+	for i, head := range heads.Heads {
+		infos[i] = &HeadInfo{
+			Index:    i,
+			Name:     heads.Names[i],
+			Primary:  i == heads.Primary,
+			Geometry: util.NewGeometry(head),
+		}
+		if i < len(heads.HeadsStruts) {
+			infos[i].GeometryStruts = util.NewGeometry(heads.HeadsStruts[i])
+		}
+	}
+	return infos
+}
+
+func getWindowInfo(win *window.Window, focusOrder, stackOrder map[xproto.Window]int) *WindowInfo {
+	info := &WindowInfo{
+		Id:         win.Id(),
+		Name:       win.Name(),
+		Groups:     groupmanager.GetWinGroups(win.Id()),
+		Layer:      layerNames[win.Layer()],
+		States:     win.GetActiveStates(),
+		Focused:    win.IsFocused(),
+		FocusOrder: -1,
+		StackOrder: -1,
+	}
+	sort.Strings(info.States)
+	if g, err := win.Geometry(); err == nil {
+		info.Geometry = util.NewGeometry(g)
+	}
+	if i, ok := focusOrder[win.Id()]; ok {
+		info.FocusOrder = i
+	}
+	if i, ok := stackOrder[win.Id()]; ok {
+		info.StackOrder = i
+	}
+	return info
+}
+
+func indexMap(ids []xproto.Window) map[xproto.Window]int {
+	m := make(map[xproto.Window]int, len(ids))
+	for i, id := range ids {
+		m[id] = i
+	}
+	return m
+}
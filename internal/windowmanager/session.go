@@ -0,0 +1,178 @@
+package windowmanager
+
+import (
+	"log"
+	"os"
+	"sort"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/icccm"
+	"github.com/janbina/swm/internal/focus"
+	"github.com/janbina/swm/internal/groupmanager"
+	"github.com/janbina/swm/internal/session"
+	"github.com/janbina/swm/internal/stack"
+	"github.com/janbina/swm/internal/window"
+)
+
+var (
+	// session being restored, only set while existing clients are managed on startup
+	restoredSession *session.Session
+	// path of the session file being restored, it is removed once the session is restored
+	restoredPath string
+	// saved states of windows restored from session
+	restoredWindows map[xproto.Window]*session.Window
+)
+
+// SessionPath returns path of the session file of current display
+func SessionPath() (string, error) {
+	return session.Path(X.Conn().DisplayNumber)
+}
+
+// SaveSession saves state of all managed windows to session file, so it can be restored after restart
+func SaveSession() error {
+	path, err := SessionPath()
+	if err != nil {
+		return err
+	}
+	s := &session.Session{}
+	saved := make(map[xproto.Window]*session.Window)
+	for id, win := range managedWindows {
+		sw := win.SessionState()
+		sw.Groups = groupmanager.GetWinGroups(id)
+		s.Windows = append(s.Windows, sw)
+		saved[id] = sw
+	}
+	for i, id := range focus.GetFocusOrder() {
+		if sw := saved[id]; sw != nil {
+			sw.Focus = i
+		}
+	}
+	for i, id := range stack.GetStackingOrder() {
+		if sw := saved[id]; sw != nil {
+			sw.Stack = i
+		}
+	}
+	sort.Slice(s.Windows, func(i, j int) bool {
+		return s.Windows[i].Focus < s.Windows[j].Focus
+	})
+	current := uint(groupmanager.GetCurrentGroup())
+	for _, g := range groupmanager.GetVisibleGroups() {
+		if g != current {
+			s.VisibleGroups = append(s.VisibleGroups, g)
+		}
+	}
+	if groupmanager.IsGroupVisible(int(current)) {
+		s.VisibleGroups = append(s.VisibleGroups, current)
+	}
+	if err := session.Save(path, s); err != nil {
+		return err
+	}
+	log.Printf("Session saved to %s", path)
+	return nil
+}
+
+// loadSession loads session which is restored while managing existing clients
+func loadSession() {
+	path, err := SessionPath()
+	if err != nil {
+		log.Printf("Cannot get session path: %s", err)
+		return
+	}
+	s, err := session.Load(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Cannot load session: %s", err)
+		}
+		return
+	}
+	restoredSession = s
+	restoredPath = path
+	restoredWindows = make(map[xproto.Window]*session.Window)
+
+	// no windows are managed yet, so there are no visibility changes to apply
+	for i, g := range s.VisibleGroups {
+		if i == 0 {
+			_ = groupmanager.ShowGroupOnly(int(g))
+		} else {
+			_ = groupmanager.ShowGroup(int(g))
+		}
+	}
+}
+
+// isInRestoredSession returns whether window is in session being restored,
+// such windows are managed even if they are not mapped (e.g. iconified or in hidden group)
+func isInRestoredSession(id xproto.Window) bool {
+	if restoredSession == nil {
+		return false
+	}
+	sw := &session.Window{Id: id}
+	if class, err := icccm.WmClassGet(X, id); err == nil {
+		sw.Class, sw.Instance = class.Class, class.Instance
+	}
+	return restoredSession.HasWindow(sw)
+}
+
+// matchSession finds saved state of window being managed, returns nil if no session is being restored
+func matchSession(win *window.Window) *session.Window {
+	if restoredSession == nil {
+		return nil
+	}
+	sw := restoredSession.Match(win.SessionIdentity())
+	if sw != nil {
+		restoredWindows[win.Id()] = sw
+	}
+	return sw
+}
+
+// restoreSessionGroups puts window to its saved groups, window is not mapped yet, so changes are not applied
+func restoreSessionGroups(win xproto.Window, sw *session.Window) {
+	for i, g := range sw.Groups {
+		if i == 0 {
+			_ = groupmanager.SetGroupForWindow(win, int(g))
+		} else {
+			_ = groupmanager.AddWindowToGroup(win, int(g))
+		}
+	}
+}
+
+// finishSessionRestore restores stacking and focus order of restored windows and ends the restoring
+func finishSessionRestore() {
+	if restoredSession == nil {
+		return
+	}
+	wins := make([]*window.Window, 0, len(restoredWindows))
+	for id := range restoredWindows {
+		if win := managedWindows[id]; win != nil {
+			wins = append(wins, win)
+		}
+	}
+
+	sort.Slice(wins, func(i, j int) bool {
+		return restoredWindows[wins[i].Id()].Stack < restoredWindows[wins[j].Id()].Stack
+	})
+	for _, win := range wins {
+		win.Raise()
+	}
+
+	// the most recently focused window is the last one
+	sort.Slice(wins, func(i, j int) bool {
+		return restoredWindows[wins[i].Id()].Focus > restoredWindows[wins[j].Id()].Focus
+	})
+	for _, win := range wins {
+		focus.SetFocus(win)
+	}
+	if len(wins) > 0 {
+		if last := wins[len(wins)-1]; last.IsFocusable() {
+			last.Focus()
+		}
+	}
+
+	log.Printf("Restored session of %d windows", len(wins))
+	// session is consumed, it must not be restored again by an unrelated start (e.g. after X server restart)
+	if err := os.Remove(restoredPath); err != nil {
+		log.Printf("Cannot remove session file: %s", err)
+	}
+	restoredSession = nil
+	restoredPath = ""
+	restoredWindows = nil
+}
@@ -36,6 +36,8 @@ var windowStateHandlers = map[string][3]func(window *win){
 	"_NET_WM_STATE_FOCUSED":           {(*win).Unfocused, (*win).Focused, (*win).FocusToggle},
 	"_NET_WM_STATE_SKIP_TASKBAR":      {(*win).UnSkipTaskbar, (*win).SkipTaskbar, (*win).ToggleSkipTaskbar},
 	"_NET_WM_STATE_SKIP_PAGER":        {(*win).UnSkipPager, (*win).SkipPager, (*win).ToggleSkipPager},
+	"_NET_WM_STATE_SHADED":            {(*win).UnShade, (*win).Shade, (*win).ShadeToggle},
+	"_NET_WM_STATE_STICKY":            {unstickWindow, stickWindow, stickToggle},
 }
 
 func handleWindowClientMessage(X *xgbutil.XUtil, e xevent.ClientMessageEvent) {
@@ -63,6 +65,14 @@ func handleMoveResizeMessage(win *win, data []uint32) {
 		win.DragResizeBegin(int16(xr), int16(yr), int(dir))
 	} else if dir == ewmh.Move {
 		win.DragMoveBegin(int16(xr), int16(yr))
+	} else if dir == ewmh.SizeKeyboard {
+		if err := win.KeyboardResizeBegin(); err != nil {
+			log.Printf("Cannot begin keyboard resize: %s", err)
+		}
+	} else if dir == ewmh.MoveKeyboard {
+		if err := win.KeyboardMoveBegin(); err != nil {
+			log.Printf("Cannot begin keyboard move: %s", err)
+		}
 	} else {
 		log.Printf("Unsupported direction: %d", dir)
 	}
@@ -71,6 +81,7 @@ func handleMoveResizeMessage(win *win, data []uint32) {
 func handleWmChangeStateMessage(win *win, data []uint32) {
 	if data[0] == icccm.StateIconic && !win.IsIconified() {
 		win.IconifyToggle()
+		relayout()
 	}
 }
 
@@ -78,6 +89,8 @@ func handleActiveWindowMessage(win *win, _ []uint32) {
 	showWindowGroup(win.Id())
 	win.Focus()
 	win.Raise()
+	// focusing iconified window deiconifies it, so it joins tiling
+	relayout()
 }
 
 func handleWmStateMessage(win *win, data []uint32) {
@@ -87,6 +100,8 @@ func handleWmStateMessage(win *win, data []uint32) {
 	log.Printf("Wm state client message: %d, %s, %s", action, p1, p2)
 
 	updateWinStates(win, int(action), p1, p2)
+	// states like hidden or fullscreen change set of tiled windows
+	relayout()
 }
 
 func updateWinStates(win *win, action int, s1 string, s2 string) {
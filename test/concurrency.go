@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+func testConcurrentCommands() int {
+	errorCnt := 0
+
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	wins := createWindows(2)
+
+	// burst of commands from many clients while other windows are being mapped
+	var wg sync.WaitGroup
+	failures := make(chan string, 100)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := intStr(int(wins[i%len(wins)].Id))
+			out, err := swmctlOut("moveresize", "-id", id, "-x", intStr(i), "-y", "0", "-w", "300", "-h", "300")
+			if err != nil || out != "" {
+				failures <- fmt.Sprintf("Moveresize failed: %v %s", err, out)
+			}
+			var infos []queryWindow
+			out, err = swmctlOut("query", "windows")
+			if err != nil || json.Unmarshal([]byte(out), &infos) != nil {
+				failures <- fmt.Sprintf("Query failed: %v %s", err, out)
+			}
+		}(i)
+	}
+	mapped := createWindows(3)
+	wg.Wait()
+	close(failures)
+
+	for f := range failures {
+		assert(false, f, &errorCnt)
+	}
+
+	// every window is managed and moved windows have geometry set by one of the commands
+	var infos []queryWindow
+	out, _ := swmctlOut("query", "windows")
+	assert(json.Unmarshal([]byte(out), &infos) == nil, "Cannot decode windows", &errorCnt)
+	for _, win := range append(wins, mapped...) {
+		found := false
+		for _, info := range infos {
+			if info.Id == win.Id {
+				found = true
+			}
+		}
+		assert(found, "Window missing in query result", &errorCnt)
+	}
+	for _, win := range wins {
+		g := geom(win)
+		assert(g.X() >= 0 && g.X() < 20, "Window should be moved by one of the commands", &errorCnt)
+		assertEquals(0, g.Y(), "Invalid y", &errorCnt)
+		assertEquals(300, g.Width(), "Invalid width", &errorCnt)
+		assertEquals(300, g.Height(), "Invalid height", &errorCnt)
+	}
+
+	destroyWindows(wins)
+	destroyWindows(mapped)
+
+	return errorCnt
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func testRedecoration() int {
+	errorCnt := 0
+
+	win := createWindow()
+	frame := geom(win)
+
+	swmctl("config", "border", "5", "B0BEC5", "00BCD4", "F44336")
+
+	// frame stays where it is, client shrinks
+	assertGeomEquals(frame, geom(win), "Frame geometry should not change", &errorCnt)
+	client, _ := win.Geometry()
+	assertGeomEquals(xrect.New(5, 5, frame.Width()-10, frame.Height()-10), client, "Invalid client geometry", &errorCnt)
+
+	extents, _ := ewmh.FrameExtentsGet(X, win.Id)
+	assert(extents != nil && extents.Left == 5 && extents.Top == 5, "Frame extents should be updated", &errorCnt)
+
+	swmctl("config", "border", "1", "B0BEC5", "00BCD4", "F44336")
+	client, _ = win.Geometry()
+	assertGeomEquals(xrect.New(1, 1, frame.Width()-2, frame.Height()-2), client, "Invalid client geometry", &errorCnt)
+
+	win.Destroy()
+
+	return errorCnt
+}
+
+func testTitleBar() int {
+	errorCnt := 0
+
+	win := createWindow()
+	id := intStr(int(win.Id))
+	frame := geom(win)
+
+	swmctl("title-bar", "-id", id, "show")
+	extents, _ := ewmh.FrameExtentsGet(X, win.Id)
+	assert(extents != nil && extents.Top == 21, "Frame extents should include title bar", &errorCnt)
+	client, _ := win.Geometry()
+	assertGeomEquals(xrect.New(1, 21, frame.Width()-2, frame.Height()-22), client, "Invalid client geometry", &errorCnt)
+
+	swmctl("config", "title-bar-height", "30")
+	client, _ = win.Geometry()
+	assertGeomEquals(xrect.New(1, 31, frame.Width()-2, frame.Height()-32), client, "Title bar height should change", &errorCnt)
+	swmctl("config", "title-bar-height", "20")
+
+	swmctl("title-bar", "-id", id, "toggle")
+	extents, _ = ewmh.FrameExtentsGet(X, win.Id)
+	assert(extents != nil && extents.Top == 1, "Title bar should be removed", &errorCnt)
+
+	// from rule
+	swmctl("rule", "add", "-class", "titled", "-title-bar")
+	titled := createWindowWithClass("titled")
+	extents, _ = ewmh.FrameExtentsGet(X, titled.Id)
+	assert(extents != nil && extents.Top == 21, "Rule should add title bar", &errorCnt)
+	swmctl("rule", "clear")
+
+	// title bars from config are added only to decorated windows
+	swmctl("config", "title-bar", "true")
+	normal := createWindow()
+	extents, _ = ewmh.FrameExtentsGet(X, normal.Id)
+	assert(extents != nil && extents.Top == 21, "Config should add title bar", &errorCnt)
+	dock := createWindowWithSetup(func(win *xwindow.Window) {
+		_ = ewmh.WmWindowTypeSet(X, win.Id, []string{"_NET_WM_WINDOW_TYPE_DOCK"})
+	})
+	extents, _ = ewmh.FrameExtentsGet(X, dock.Id)
+	assert(extents == nil || extents.Top == 0, "Dock should not get title bar", &errorCnt)
+	swmctl("config", "title-bar", "false")
+
+	win.Destroy()
+	titled.Destroy()
+	normal.Destroy()
+	dock.Destroy()
+
+	return errorCnt
+}
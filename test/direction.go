@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+)
+
+func testDirectionalFocus() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 2)
+	waitForPropertyChange(X.RootWin(), "_NET_NUMBER_OF_DESKTOPS")
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	// 2x2 grid: 0 1
+	//           2 3
+	wins := createWindows(4)
+	swmctl("group", "layout", "0", "grid")
+
+	_ = ewmh.ActiveWindowReq(X, wins[0].Id)
+	assertActive(wins[0], &errorCnt)
+
+	swmctl("focus", "-d", "east")
+	assertActive(wins[1], &errorCnt)
+	swmctl("focus", "-d", "south")
+	assertActive(wins[3], &errorCnt)
+	swmctl("focus", "-d", "west")
+	assertActive(wins[2], &errorCnt)
+	swmctl("focus", "-d", "north")
+	assertActive(wins[0], &errorCnt)
+
+	// nothing to the west
+	out, _ := swmctlOut("focus", "-d", "west")
+	assert(len(out) > 0, "Focusing nonexistent window should fail", &errorCnt)
+	assertActive(wins[0], &errorCnt)
+
+	// wraps around to the farthest window on the other side
+	swmctl("focus", "-d", "west", "-wrap")
+	assertActive(wins[1], &errorCnt)
+
+	out, _ = swmctlOut("focus", "-d", "up")
+	assert(len(out) > 0, "Invalid direction should be refused", &errorCnt)
+
+	swmctl("group", "layout", "0", "floating")
+	destroyWindows(wins)
+
+	return errorCnt
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os/exec"
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+)
+
+type event struct {
+	Type   string        `json:"event"`
+	Window xproto.Window `json:"window"`
+}
+
+func testEvents() int {
+	errorCnt := 0
+
+	cmd := exec.Command("./swmctl", "subscribe", "window-managed", "window-unmanaged")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil || cmd.Start() != nil {
+		assert(false, "Cannot subscribe", &errorCnt)
+		return errorCnt
+	}
+	defer func() { _ = cmd.Process.Kill() }()
+
+	received := make(chan event, 10)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var e event
+			if json.Unmarshal(scanner.Bytes(), &e) == nil {
+				received <- e
+			}
+		}
+	}()
+	// give swmctl time to connect
+	time.Sleep(100 * time.Millisecond)
+
+	win := createWindow()
+	e := waitForSwmEvent(received)
+	assert(e.Type == "window-managed" && e.Window == win.Id, "Expected window-managed event", &errorCnt)
+
+	win.Destroy()
+	e = waitForSwmEvent(received)
+	assert(e.Type == "window-unmanaged" && e.Window == win.Id, "Expected window-unmanaged event", &errorCnt)
+
+	// invalid event name is refused
+	_, err = swmctlOut("subscribe", "no-such-event")
+	assert(err != nil, "Subscribing to unknown event should fail", &errorCnt)
+
+	return errorCnt
+}
+
+func waitForSwmEvent(events chan event) event {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(1 * time.Second):
+		return event{}
+	}
+}
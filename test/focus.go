@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func testFocusModel() int {
+	errorCnt := 0
+
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	out, _ := swmctlOut("config", "focus-model", "eyes")
+	assert(len(out) > 0, "Invalid focus model should be refused", &errorCnt)
+
+	// windows side by side
+	wins := createWindows(2)
+	swmctl("group", "layout", "0", "grid")
+	_ = ewmh.ActiveWindowReq(X, wins[0].Id)
+	assertActive(wins[0], &errorCnt)
+
+	swmctl("config", "focus-model", "sloppy")
+	warpPointerTo(wins[1])
+	assertActive(wins[1], &errorCnt)
+	warpPointerTo(wins[0])
+	assertActive(wins[0], &errorCnt)
+
+	// click to focus ignores the pointer
+	swmctl("config", "focus-model", "click")
+	warpPointerTo(wins[1])
+	assertActive(wins[0], &errorCnt)
+
+	swmctl("group", "layout", "0", "floating")
+	destroyWindows(wins)
+
+	return errorCnt
+}
+
+func testIgnoredEnterEvents() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 2)
+	waitForPropertyChange(X.RootWin(), "_NET_NUMBER_OF_DESKTOPS")
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	// a and b on the same place in different groups, c elsewhere in the group of b
+	wins := createWindows(3)
+	a, b, c := wins[0], wins[1], wins[2]
+	for _, win := range wins[:2] {
+		swmctl("moveresize", "-id", intStr(int(win.Id)), "-x", "0", "-y", "0", "-w", "400", "-h", "400")
+	}
+	swmctl("moveresize", "-id", intStr(int(c.Id)), "-x", "500", "-y", "0", "-w", "200", "-h", "200")
+	for _, win := range wins[1:] {
+		swmctl("group", "set", "-g", "1", "-id", intStr(int(win.Id)))
+	}
+	_ = ewmh.ActiveWindowReq(X, b.Id)
+	assertActive(b, &errorCnt)
+	_ = ewmh.ActiveWindowReq(X, c.Id)
+	assertActive(c, &errorCnt)
+	swmctl("group", "only", "0")
+	assertActive(a, &errorCnt)
+
+	swmctl("config", "focus-model", "sloppy")
+	warpPointerTo(a)
+
+	// switching groups maps b under the pointer, but focus goes to the last focused window of the group
+	flushEvents()
+	swmctl("group", "only", "1")
+	waitForActive(b.Id)
+	assertActive(c, &errorCnt)
+
+	// user moving the pointer still changes focus
+	warpPointerTo(c)
+	warpPointerTo(b)
+	assertActive(b, &errorCnt)
+
+	swmctl("config", "focus-model", "click")
+	destroyWindows(wins)
+
+	return errorCnt
+}
+
+func warpPointerTo(win *xwindow.Window) {
+	g := geom(win)
+	xproto.WarpPointer(X.Conn(), 0, X.RootWin(), 0, 0, 0, 0, int16(g.X()+g.Width()/2), int16(g.Y()+g.Height()/2))
+	X.Sync()
+}
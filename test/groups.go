@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xprop"
 	"github.com/BurntSushi/xgbutil/xwindow"
 )
 
@@ -288,6 +289,83 @@ func testGroupMembership() int {
 	return errorCnt
 }
 
+func testStickyState() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 2)
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	win := createWindow()
+	winId := intStr(int(win.Id))
+
+	// sticky state adds window to sticky group
+	_ = ewmh.WmStateReqExtra(X, win.Id, ewmh.StateAdd, "_NET_WM_STATE_STICKY", "", 2)
+	waitForPropertyChange(win.Id, "_NET_WM_DESKTOP")
+	assertSliceEquals([]int{0, 0xFFFFFFFF}, getIntsFromSwm("group", "get", "-id", winId), "Incorrect window groups", &errorCnt)
+	desktops, _ := xprop.PropValNums(xprop.GetProperty(X, win.Id, "_NET_WM_DESKTOP"))
+	assertSliceEquals([]int{0xFFFFFFFF}, uintsToInts(desktops), "Sticky window should be only on all desktops", &errorCnt)
+
+	// removing it removes window from sticky group
+	_ = ewmh.WmStateReqExtra(X, win.Id, ewmh.StateRemove, "_NET_WM_STATE_STICKY", "", 2)
+	waitForPropertyChange(win.Id, "_NET_WM_DESKTOP")
+	assertSliceEquals([]int{0}, getIntsFromSwm("group", "get", "-id", winId), "Incorrect window groups", &errorCnt)
+	d, _ := ewmh.WmDesktopGet(X, win.Id)
+	assertEquals(0, int(d), "Unstuck window should be back in its group", &errorCnt)
+
+	// moving window to sticky group makes it sticky
+	_ = ewmh.WmDesktopReqExtra(X, win.Id, 0xFFFFFFFF, 2)
+	waitForPropertyChange(win.Id, "_NET_WM_STATE")
+	states, _ := ewmh.WmStateGet(X, win.Id)
+	assert(contains(states, "_NET_WM_STATE_STICKY"), "Window should be sticky", &errorCnt)
+
+	// window only in sticky group is moved to the current group when unstuck
+	_ = ewmh.WmStateReqExtra(X, win.Id, ewmh.StateToggle, "_NET_WM_STATE_STICKY", "", 2)
+	waitForPropertyChange(win.Id, "_NET_WM_DESKTOP")
+	assertSliceEquals([]int{0}, getIntsFromSwm("group", "get", "-id", winId), "Incorrect window groups", &errorCnt)
+	states, _ = ewmh.WmStateGet(X, win.Id)
+	assert(!contains(states, "_NET_WM_STATE_STICKY"), "Window should not be sticky", &errorCnt)
+
+	win.Destroy()
+
+	return errorCnt
+}
+
+func testPerHeadGroups() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 3)
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	// head cannot be targeted without per-head mode
+	out, _ := swmctlOut("group", "only", "1", "-head", "0")
+	assert(out != "", "Head should be rejected when per-head mode is disabled", &errorCnt)
+
+	swmctl("group", "per-head", "on")
+
+	swmctl("group", "only", "1", "-head", "0")
+	assertSliceEquals([]int{1}, getIntsFromSwm("group", "get-visible"), "Incorrect visible groups", &errorCnt)
+	assertEquals(1, activeDesktop(), "Incorrect active desktop", &errorCnt)
+
+	swmctl("group", "show", "2", "-head", "0")
+	assertSliceEquals([]int{1, 2}, getIntsFromSwm("group", "get-visible"), "Incorrect visible groups", &errorCnt)
+
+	// groups of the same head can be swapped without any change
+	swmctl("group", "swap", "0", "0")
+	assertSliceEquals([]int{1, 2}, getIntsFromSwm("group", "get-visible"), "Incorrect visible groups", &errorCnt)
+
+	out, _ = swmctlOut("group", "only", "1", "-head", "nonexistent")
+	assert(out != "", "Invalid head should be rejected", &errorCnt)
+	out, _ = swmctlOut("group", "hide", "1", "-head", "0")
+	assert(out != "", "Head should be rejected for hide", &errorCnt)
+
+	swmctl("group", "per-head", "off")
+	swmctl("group", "only", "0")
+
+	return errorCnt
+}
+
 func activeDesktop() int {
 	d, _ := ewmh.CurrentDesktopGet(X)
 	return int(d)
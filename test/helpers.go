@@ -17,6 +17,11 @@ import (
 )
 
 func createWindow() *xwindow.Window {
+	return createWindowWithSetup(nil)
+}
+
+// creates window and calls setup before it is mapped
+func createWindowWithSetup(setup func(win *xwindow.Window)) *xwindow.Window {
 	win, err := xwindow.Generate(X)
 	if err != nil {
 		log.Fatal(err)
@@ -36,6 +41,10 @@ func createWindow() *xwindow.Window {
 		xproto.EventMaskFocusChange,
 	)
 
+	if setup != nil {
+		setup(win)
+	}
+
 	win.Map()
 
 	active, reparented, mapped := false, false, false
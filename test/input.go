@@ -0,0 +1,55 @@
+package main
+
+import (
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgb/xtest"
+	"github.com/BurntSushi/xgbutil/keybind"
+)
+
+// swm handles the input asynchronously, so give it some time before checking the result
+const inputDelay = 50 * time.Millisecond
+
+func fakeInput(eventType byte, detail byte, x, y int) {
+	_ = xtest.FakeInputChecked(X.Conn(), eventType, detail, 0, X.RootWin(), int16(x), int16(y), 0).Check()
+	time.Sleep(inputDelay)
+}
+
+// moves pointer to given position on the root window
+func fakeMotion(x, y int) {
+	fakeInput(xproto.MotionNotify, 0, x, y)
+}
+
+func fakeButton(eventType byte, button byte) {
+	fakeInput(eventType, button, 0, 0)
+}
+
+// presses or releases keys given by their names, e.g. Shift_L or Right
+func fakeKeys(eventType byte, keys ...string) {
+	for _, key := range keys {
+		codes := keybind.StrToKeycodes(X, key)
+		if len(codes) > 0 {
+			fakeInput(eventType, byte(codes[0]), 0, 0)
+		}
+	}
+}
+
+// presses and releases key while holding given modifier keys
+func fakeKeyPress(key string, modifiers ...string) {
+	fakeKeys(xproto.KeyPress, modifiers...)
+	fakeKeys(xproto.KeyPress, key)
+	fakeKeys(xproto.KeyRelease, key)
+	fakeKeys(xproto.KeyRelease, modifiers...)
+}
+
+// drags pointer with the first button pressed while holding given modifier keys
+func fakeDrag(fromX, fromY, toX, toY int, modifiers ...string) {
+	fakeMotion(fromX, fromY)
+	fakeKeys(xproto.KeyPress, modifiers...)
+	fakeButton(xproto.ButtonPress, 1)
+	fakeMotion((fromX+toX)/2, (fromY+toY)/2)
+	fakeMotion(toX, toY)
+	fakeButton(xproto.ButtonRelease, 1)
+	fakeKeys(xproto.KeyRelease, modifiers...)
+}
@@ -0,0 +1,36 @@
+package main
+
+import "strings"
+
+func testKeyBindings() int {
+	errorCnt := 0
+
+	swmctl("bind", "Mod4-Return", "exec", "true")
+	swmctl("bind", "-release", "Alt_L", "cycle-win-end")
+	swmctl("bind", "Mod4-w ;f", "group", "only", "0")
+
+	out, _ := swmctlOut("bind")
+	assert(strings.Contains(out, `"Mod4-Return" exec true`), "Binding should be listed", &errorCnt)
+	assert(strings.Contains(out, `-release "Alt_L" cycle-win-end`), "Release binding should be listed", &errorCnt)
+	assert(strings.Contains(out, `"Mod4-w ; f" group only 0`), "Chord should be listed", &errorCnt)
+
+	// rebinding replaces previous binding
+	swmctl("bind", "Mod4-Return", "exec", "false")
+	out, _ = swmctlOut("bind")
+	assert(!strings.Contains(out, "exec true"), "Binding should be replaced", &errorCnt)
+
+	swmctl("unbind", "Mod4-Return")
+	out, _ = swmctlOut("bind")
+	assert(!strings.Contains(out, "Mod4-Return"), "Binding should be removed", &errorCnt)
+
+	out, _ = swmctlOut("unbind", "Mod4-Return")
+	assert(len(out) > 0, "Unbinding nonexistent binding should fail", &errorCnt)
+	out, _ = swmctlOut("bind", "Mod4-NoSuchKey", "cycle-win")
+	assert(len(out) > 0, "Invalid keys should be refused", &errorCnt)
+
+	swmctl("unbind", "-all")
+	out, _ = swmctlOut("bind")
+	assert(len(out) == 0, "All bindings should be removed", &errorCnt)
+
+	return errorCnt
+}
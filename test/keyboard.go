@@ -0,0 +1,54 @@
+package main
+
+import (
+	"time"
+
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+func testKeyboardMoveResize() int {
+	errorCnt := 0
+
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	wins := createWindows(2)
+	win := wins[0]
+	id := intStr(int(win.Id))
+	swmctl("moveresize", "-id", id, "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+
+	// keyboard move focuses the window, arrows move it by small and with modifier by big steps
+	swmctl("begin-keyboard-move", "-id", id)
+	assertActive(win, &errorCnt)
+	out, _ := swmctlOut("begin-keyboard-move", "-id", id)
+	assert(len(out) > 0, "Keyboard mode should not begin twice", &errorCnt)
+	fakeKeyPress("Right")
+	fakeKeyPress("Down", "Shift_L")
+	assertGeomEquals(xrect.New(110, 200, 200, 200), geom(win), "Window should be moved by keyboard", &errorCnt)
+	fakeKeyPress("Return")
+	assertGeomEquals(xrect.New(110, 200, 200, 200), geom(win), "Return should keep geometry", &errorCnt)
+
+	// keys are not handled after the mode ends
+	fakeKeyPress("Right")
+	assertGeomEquals(xrect.New(110, 200, 200, 200), geom(win), "Window should not be moved after the mode ends", &errorCnt)
+
+	// keyboard resize started by client, escape restores starting geometry
+	_ = ewmh.WmMoveresize(X, win.Id, ewmh.SizeKeyboard)
+	time.Sleep(inputDelay)
+	fakeKeyPress("Right")
+	fakeKeyPress("Up", "Shift_L")
+	assertGeomEquals(xrect.New(110, 200, 210, 100), geom(win), "Window should be resized by keyboard", &errorCnt)
+	fakeKeyPress("Escape")
+	assertGeomEquals(xrect.New(110, 200, 200, 200), geom(win), "Escape should restore geometry", &errorCnt)
+
+	// mode can begin again once it ended
+	swmctl("begin-keyboard-move", "-id", id)
+	fakeKeyPress("Left")
+	fakeKeyPress("Return")
+	assertGeomEquals(xrect.New(100, 200, 200, 200), geom(win), "Window should be moved by keyboard", &errorCnt)
+
+	destroyWindows(wins)
+
+	return errorCnt
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func testLayout() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 2)
+	waitForPropertyChange(X.RootWin(), "_NET_NUMBER_OF_DESKTOPS")
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	screen, _ := xwindow.New(X, X.RootWin()).Geometry()
+	halfW := screen.Width() / 2
+	halfH := screen.Height() / 2
+
+	wins := createWindows(3)
+
+	// master on the left, two windows stacked on the right
+	swmctl("group", "layout", "0", "tile-left")
+	assertGeomEquals(xrect.New(0, 0, halfW, screen.Height()), geom(wins[0]), "Invalid master geometry", &errorCnt)
+	assertGeomEquals(xrect.New(halfW, 0, screen.Width()-halfW, halfH), geom(wins[1]), "Invalid stack geometry", &errorCnt)
+	assertGeomEquals(xrect.New(halfW, halfH, screen.Width()-halfW, screen.Height()-halfH), geom(wins[2]), "Invalid stack geometry", &errorCnt)
+
+	// two masters
+	swmctl("group", "master-count", "0", "+1")
+	assertGeomEquals(xrect.New(0, 0, halfW, halfH), geom(wins[0]), "Invalid master geometry", &errorCnt)
+	assertGeomEquals(xrect.New(halfW, 0, screen.Width()-halfW, screen.Height()), geom(wins[2]), "Invalid stack geometry", &errorCnt)
+	swmctl("group", "master-count", "0", "1")
+
+	// removed window is re-arranged
+	wins[2].Destroy()
+	waitForConfigureNotify()
+	assertGeomEquals(xrect.New(halfW, 0, screen.Width()-halfW, screen.Height()), geom(wins[1]), "Invalid stack geometry", &errorCnt)
+
+	// iconified window leaves tiling and activating it brings it back
+	flushEvents()
+	_ = ewmh.WmStateReqExtra(X, wins[1].Id, ewmh.StateAdd, "_NET_WM_STATE_HIDDEN", "", 2)
+	waitForUnmapNotify()
+	assertGeomEquals(screen, geom(wins[0]), "Master should fill the screen", &errorCnt)
+	flushEvents()
+	_ = ewmh.ActiveWindowReq(X, wins[1].Id)
+	waitForMapNotify()
+	assertActive(wins[1], &errorCnt)
+	assertGeomEquals(xrect.New(0, 0, halfW, screen.Height()), geom(wins[0]), "Invalid master geometry", &errorCnt)
+	assertGeomEquals(xrect.New(halfW, 0, screen.Width()-halfW, screen.Height()), geom(wins[1]), "Invalid stack geometry", &errorCnt)
+
+	// window in two visible tiled groups is arranged only once, by the first group
+	swmctl("group", "add", "-g", "1", "-id", intStr(int(wins[1].Id)))
+	swmctl("group", "layout", "1", "monocle")
+	swmctl("group", "show", "1")
+	assertGeomEquals(xrect.New(halfW, 0, screen.Width()-halfW, screen.Height()), geom(wins[1]), "Window should be arranged by the first group", &errorCnt)
+	swmctl("group", "hide", "1")
+	swmctl("group", "layout", "1", "floating")
+	swmctl("group", "remove", "-g", "1", "-id", intStr(int(wins[1].Id)))
+
+	// monocle
+	swmctl("group", "layout", "0", "monocle")
+	for _, win := range wins[:2] {
+		assertGeomEquals(screen, geom(win), "Invalid monocle geometry", &errorCnt)
+	}
+
+	// floating is the default and leaves windows where they are
+	swmctl("group", "layout", "0", "floating")
+	swmctl("moveresize", "-id", intStr(int(wins[0].Id)), "-x", "10", "-y", "10", "-w", "100", "-h", "100")
+	createWindow().Destroy()
+	assertGeomEquals(xrect.New(10, 10, 100, 100), geom(wins[0]), "Floating window should not move", &errorCnt)
+
+	out, _ := swmctlOut("group", "layout", "0", "spiral")
+	assert(len(out) > 0, "Invalid layout should be refused", &errorCnt)
+
+	destroyWindows(wins[:2])
+
+	return errorCnt
+}
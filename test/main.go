@@ -9,7 +9,9 @@ import (
 
 	"github.com/BurntSushi/xgb"
 	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgb/xtest"
 	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/keybind"
 	"github.com/BurntSushi/xgbutil/xwindow"
 )
 
@@ -21,6 +23,7 @@ type test struct {
 }
 
 var tests = []test{
+	{"concurrent commands", testConcurrentCommands},
 	{"cycling", testCycling},
 	{"desktop names", testDesktopNames},
 	{"group basics", testGroupBasics},
@@ -28,10 +31,33 @@ var tests = []test{
 	{"group window movement", testGroupWindowMovement},
 	{"group visibility", testGroupVisibility},
 	{"group membership", testGroupMembership},
+	{"sticky state", testStickyState},
+	{"per-head groups", testPerHeadGroups},
 	{"moving command", testMovingCommand},
 	{"resizing command", testResizingCommand},
+	{"resize handles", testResizeHandles},
 	{"moveresize command", testMoveResizeCommand},
+	{"head send command", testHeadSendCommand},
 	{"window states", testWindowStates},
+	{"shading", testShading},
+	{"query", testQuery},
+	{"query heads", testQueryHeads},
+	{"events", testEvents},
+	{"rules", testRules},
+	{"placement", testPlacement},
+	{"layout", testLayout},
+	{"directional focus", testDirectionalFocus},
+	{"focus model", testFocusModel},
+	{"ignored enter events", testIgnoredEnterEvents},
+	{"snap and swap", testSnapAndSwap},
+	{"drag snapping", testDragSnapping},
+	{"key bindings", testKeyBindings},
+	{"keyboard move and resize", testKeyboardMoveResize},
+	{"redecoration", testRedecoration},
+	{"title bar", testTitleBar},
+	{"session", testSession},
+	// restart replaces swm process, keep it last
+	{"restart", testRestart},
 }
 
 var errorLogger = log.New(os.Stdout, "    error: ", log.Lshortfile)
@@ -45,6 +71,12 @@ func main() {
 	}
 	defer X.Conn().Close()
 
+	// input is simulated by xtest, key names are translated to keycodes by keybind
+	if err := xtest.Init(X.Conn()); err != nil {
+		log.Fatal(err)
+	}
+	keybind.Initialize(X)
+
 	_ = xwindow.New(X, X.RootWin()).Listen(
 		xproto.EventMaskPropertyChange,
 		xproto.EventMaskSubstructureNotify,
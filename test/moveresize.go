@@ -146,6 +146,27 @@ func testMoveResizeCommand() int {
 	return errorCnt
 }
 
+func testHeadSendCommand() int {
+	errorCnt := 0
+
+	win := createWindow()
+	winId := intStr(int(win.Id))
+	before := geom(win)
+
+	// test display has a single head, so window stays where it is
+	swmctl("head", "send", "-id", winId, "next")
+	assertGeomEquals(before, geom(win), "Window shouldn't move", &errorCnt)
+	swmctl("head", "send", "-id", winId, "0")
+	assertGeomEquals(before, geom(win), "Window shouldn't move", &errorCnt)
+
+	out, _ := swmctlOut("head", "send", "-id", winId, "nonexistent")
+	assert(out != "", "Invalid head should be rejected", &errorCnt)
+
+	win.Destroy()
+
+	return errorCnt
+}
+
 type screenGeom struct {
 	rect xrect.Rect
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/icccm"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func testPlacement() int {
+	errorCnt := 0
+
+	swmctl("group", "mode", "sticky")
+
+	// cascade - each window is moved by constant step from the previous one
+	swmctl("config", "placement", "cascade")
+	wins := createWindows(3)
+	for i := 1; i < len(wins); i++ {
+		prev, cur := geom(wins[i-1]), geom(wins[i])
+		assertEquals(prev.X()+30, cur.X(), "Incorrect cascade x", &errorCnt)
+		assertEquals(prev.Y()+30, cur.Y(), "Incorrect cascade y", &errorCnt)
+	}
+	destroyWindows(wins)
+
+	// smart - windows don't overlap while there is enough space
+	swmctl("config", "placement", "smart")
+	wins = createWindows(3)
+	for i := range wins {
+		for j := i + 1; j < len(wins); j++ {
+			a, b := geom(wins[i]), geom(wins[j])
+			overlaps := a.X() < b.X()+b.Width() && b.X() < a.X()+a.Width() &&
+				a.Y() < b.Y()+b.Height() && b.Y() < a.Y()+a.Height()
+			assert(!overlaps, "Windows should not overlap", &errorCnt)
+		}
+	}
+	destroyWindows(wins)
+
+	// center-on-parent - transient is centered on its parent, dialog without parent on the head
+	swmctl("config", "placement", "center-on-parent")
+	parent := createWindow()
+	swmctl("moveresize", "-id", intStr(int(parent.Id)), "-x", "100", "-y", "100", "-w", "600", "-h", "400")
+	transient := createWindowWithSetup(func(win *xwindow.Window) {
+		_ = icccm.WmTransientForSet(X, win.Id, parent.Id)
+	})
+	assertCentered(geom(transient), geom(parent), "Transient should be centered on parent", &errorCnt)
+	dialog := createWindowWithSetup(func(win *xwindow.Window) {
+		_ = ewmh.WmWindowTypeSet(X, win.Id, []string{"_NET_WM_WINDOW_TYPE_DIALOG"})
+	})
+	screen, _ := xwindow.New(X, X.RootWin()).Geometry()
+	assertCentered(geom(dialog), screen, "Dialog without parent should be centered on the head", &errorCnt)
+	destroyWindows([]*xwindow.Window{transient, dialog, parent})
+
+	out, _ := swmctlOut("config", "placement", "nowhere")
+	assert(len(out) > 0, "Invalid placement should be refused", &errorCnt)
+
+	swmctl("config", "placement", "pointer")
+	swmctl("group", "mode", "auto")
+
+	return errorCnt
+}
+
+func assertCentered(rect, container xrect.Rect, msg string, errorCnt *int) {
+	dX := rect.X() + rect.Width()/2 - (container.X() + container.Width()/2)
+	dY := rect.Y() + rect.Height()/2 - (container.Y() + container.Height()/2)
+	if dX < -1 || dX > 1 || dY < -1 || dY > 1 {
+		_ = errorLogger.Output(2, msg)
+		*errorCnt++
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+type queryWindow struct {
+	Id       xproto.Window `json:"id"`
+	Geometry struct {
+		X      int `json:"x"`
+		Y      int `json:"y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"geometry"`
+	Groups     []int `json:"groups"`
+	Focused    bool  `json:"focused"`
+	FocusOrder int   `json:"focus_order"`
+	StackOrder int   `json:"stack_order"`
+}
+
+type queryGroup struct {
+	Id      int             `json:"id"`
+	Visible bool            `json:"visible"`
+	Windows []xproto.Window `json:"windows"`
+}
+
+func testQuery() int {
+	errorCnt := 0
+
+	wins := createWindows(3)
+	last := wins[len(wins)-1]
+
+	var infos []queryWindow
+	out, _ := swmctlOut("query", "windows")
+	assert(json.Unmarshal([]byte(out), &infos) == nil, "Cannot decode windows", &errorCnt)
+	for _, win := range wins {
+		found := false
+		for _, info := range infos {
+			if info.Id == win.Id {
+				found = true
+			}
+		}
+		assert(found, "Window missing in query result", &errorCnt)
+	}
+
+	// active window is focused, the most recently focused and raised
+	var info queryWindow
+	out, _ = swmctlOut("query", "window")
+	assert(json.Unmarshal([]byte(out), &info) == nil, "Cannot decode window", &errorCnt)
+	assert(info.Id == last.Id, "Invalid active window", &errorCnt)
+	assert(info.Focused, "Window should be focused", &errorCnt)
+	assertEquals(0, info.FocusOrder, "Invalid focus order", &errorCnt)
+	g := geom(last)
+	assertEquals(g.X(), info.Geometry.X, "Invalid x", &errorCnt)
+	assertEquals(g.Y(), info.Geometry.Y, "Invalid y", &errorCnt)
+	assertEquals(g.Width(), info.Geometry.Width, "Invalid width", &errorCnt)
+	assertEquals(g.Height(), info.Geometry.Height, "Invalid height", &errorCnt)
+
+	var prev queryWindow
+	out, _ = swmctlOut("query", "window", "-id", intStr(int(wins[0].Id)))
+	assert(json.Unmarshal([]byte(out), &prev) == nil, "Cannot decode window", &errorCnt)
+	assert(!prev.Focused, "Window should not be focused", &errorCnt)
+	assertEquals(len(wins)-1, prev.FocusOrder, "Invalid focus order", &errorCnt)
+	assert(prev.StackOrder < info.StackOrder, "Invalid stack order", &errorCnt)
+
+	// groups contain their windows
+	var groups []queryGroup
+	out, _ = swmctlOut("query", "groups")
+	assert(json.Unmarshal([]byte(out), &groups) == nil, "Cannot decode groups", &errorCnt)
+	for _, g := range info.Groups {
+		found := false
+		for _, group := range groups {
+			if group.Id != g {
+				continue
+			}
+			for _, w := range group.Windows {
+				if w == last.Id {
+					found = true
+				}
+			}
+		}
+		assert(found, "Window missing in its group", &errorCnt)
+	}
+
+	destroyWindows(wins)
+
+	return errorCnt
+}
+
+type queryHead struct {
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Primary  bool   `json:"primary"`
+	Geometry struct {
+		X      int `json:"x"`
+		Y      int `json:"y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"geometry"`
+}
+
+func testQueryHeads() int {
+	errorCnt := 0
+
+	// test display has a single monitor covering the whole screen
+	var heads []queryHead
+	out, _ := swmctlOut("query", "heads")
+	assert(json.Unmarshal([]byte(out), &heads) == nil, "Cannot decode heads", &errorCnt)
+	if len(heads) != 1 {
+		assertEquals(1, len(heads), "Invalid number of heads", &errorCnt)
+		return errorCnt
+	}
+	head := heads[0]
+	screen, _ := xwindow.New(X, X.RootWin()).Geometry()
+	assertEquals(0, head.Index, "Invalid index", &errorCnt)
+	assert(head.Name != "", "Head should have output name", &errorCnt)
+	assert(head.Primary, "Only head should be primary", &errorCnt)
+	assertGeomEquals(
+		screen,
+		xrect.New(head.Geometry.X, head.Geometry.Y, head.Geometry.Width, head.Geometry.Height),
+		"Head should cover the screen",
+		&errorCnt,
+	)
+
+	// heads can be referred to by their name
+	win := createWindow()
+	before := geom(win)
+	out, _ = swmctlOut("head", "send", "-id", intStr(int(win.Id)), head.Name)
+	assert(out == "", "Head should be found by its name", &errorCnt)
+	assertGeomEquals(before, geom(win), "Window shouldn't move", &errorCnt)
+
+	swmctl("group", "per-head", "on")
+	out, _ = swmctlOut("group", "only", "0", "-head", head.Name)
+	assert(out == "", "Head should be found by its name", &errorCnt)
+	swmctl("group", "per-head", "off")
+
+	win.Destroy()
+
+	return errorCnt
+}
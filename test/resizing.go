@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
 )
 
 func testResizingCommand() int {
@@ -66,3 +67,42 @@ func testResizingCommand() int {
 
 	return errorCnt
 }
+
+func testResizeHandles() int {
+	errorCnt := 0
+
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+	swmctl("config", "border", "1", "B0BEC5", "00BCD4", "F44336")
+
+	win := createWindow()
+	swmctl("moveresize", "-id", intStr(int(win.Id)), "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+
+	// middle of the right border resizes only the width
+	fakeDrag(299, 200, 349, 220)
+	assertGeomEquals(xrect.New(100, 100, 250, 200), geom(win), "Right border should resize width", &errorCnt)
+
+	// corners resize in both directions
+	fakeDrag(349, 299, 379, 339)
+	assertGeomEquals(xrect.New(100, 100, 280, 240), geom(win), "Bottom right corner should resize both dimensions", &errorCnt)
+	fakeDrag(100, 100, 80, 90)
+	assertGeomEquals(xrect.New(80, 90, 300, 250), geom(win), "Top left corner should resize both dimensions", &errorCnt)
+
+	// corner zone reaches along the border
+	fakeDrag(379, 325, 389, 335)
+	assertGeomEquals(xrect.New(80, 90, 310, 260), geom(win), "Border near the corner should resize both dimensions", &errorCnt)
+
+	// client area next to the border is not a handle without margin
+	fakeDrag(386, 200, 406, 200)
+	assertGeomEquals(xrect.New(80, 90, 310, 260), geom(win), "Client area should not resize the window", &errorCnt)
+
+	// resize margin makes the handle reach inside the frame
+	swmctl("config", "resize-margin", "5")
+	fakeDrag(386, 200, 406, 200)
+	assertGeomEquals(xrect.New(80, 90, 330, 260), geom(win), "Resize margin should resize the window", &errorCnt)
+	swmctl("config", "resize-margin", "0")
+
+	destroyWindows([]*xwindow.Window{win})
+
+	return errorCnt
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func testRestart() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 2)
+	waitForPropertyChange(X.RootWin(), "_NET_NUMBER_OF_DESKTOPS")
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	wins := createWindows(2)
+	id0, id1 := intStr(int(wins[0].Id)), intStr(int(wins[1].Id))
+	swmctl("moveresize", "-id", id0, "-x", "10", "-y", "20", "-w", "300", "-h", "200")
+	swmctl("moveresize", "-id", id1, "-x", "400", "-y", "300", "-w", "200", "-h", "100")
+	swmctl("group", "add", "-id", id1, "-g", "1")
+	_ = ewmh.ActiveWindowReq(X, wins[0].Id)
+	assertActive(wins[0], &errorCnt)
+	before := geom(wins[0])
+
+	// reply is sent before swm is replaced by the new process
+	out, err := swmctlOut("restart")
+	assert(err == nil && out == "", "Restart should reply with no error", &errorCnt)
+
+	infos := waitForManaged(wins)
+	assertEquals(len(wins), len(infos), "All windows should be managed after restart", &errorCnt)
+	assertGeomEquals(before, geom(wins[0]), "Geometry should be preserved", &errorCnt)
+	if info, ok := infos[wins[1].Id]; ok {
+		assertSliceEquals([]int{0, 1}, info.Groups, "Groups should be preserved", &errorCnt)
+		assertEquals(400, info.Geometry.X, "Invalid x", &errorCnt)
+		assertEquals(300, info.Geometry.Y, "Invalid y", &errorCnt)
+	}
+	assertActive(wins[0], &errorCnt)
+
+	destroyWindows(wins)
+
+	return errorCnt
+}
+
+// waits until the restarted swm responds and manages all given windows, returns their info
+func waitForManaged(wins []*xwindow.Window) map[xproto.Window]queryWindow {
+	managed := make(map[xproto.Window]queryWindow)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			return managed
+		case <-time.After(100 * time.Millisecond):
+		}
+		var infos []queryWindow
+		out, err := swmctlOut("query", "windows")
+		if err != nil || json.Unmarshal([]byte(out), &infos) != nil {
+			continue
+		}
+		for _, info := range infos {
+			for _, win := range wins {
+				if info.Id == win.Id {
+					managed[win.Id] = info
+				}
+			}
+		}
+		if len(managed) == len(wins) {
+			return managed
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/icccm"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func createWindowWithClass(class string) *xwindow.Window {
+	return createWindowWithSetup(func(win *xwindow.Window) {
+		_ = icccm.WmClassSet(X, win.Id, &icccm.WmClass{Instance: class, Class: class})
+	})
+}
+
+func testRules() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 5)
+	waitForPropertyChange(X.RootWin(), "_NET_NUMBER_OF_DESKTOPS")
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	swmctl("rule", "add", "-class", "swm-rule-group", "-group", "2")
+	swmctl("rule", "add", "-class", "swm-rule-geom", "-x", "10", "-y", "20", "-w", "300", "-h", "100")
+	swmctl("rule", "add", "-class", "swm-rule-geom", "-layer", "above")
+
+	// group rule
+	win := createWindowWithClass("swm-rule-group")
+	d, _ := ewmh.WmDesktopGet(X, win.Id)
+	assertEquals(2, int(d), "Incorrect desktop for window", &errorCnt)
+	win.Destroy()
+
+	// more rules are merged together
+	win = createWindowWithClass("swm-rule-geom")
+	assertGeomEquals(xrect.New(10, 20, 300, 100), geom(win), "Incorrect geometry", &errorCnt)
+	states, _ := ewmh.WmStateGet(X, win.Id)
+	assert(contains(states, "_NET_WM_STATE_ABOVE"), "Window should be above", &errorCnt)
+	win.Destroy()
+
+	// not matching window is left untouched
+	win = createWindowWithClass("swm-rule-other")
+	d, _ = ewmh.WmDesktopGet(X, win.Id)
+	assertEquals(0, int(d), "Incorrect desktop for window", &errorCnt)
+	win.Destroy()
+
+	rules, _ := swmctlOut("rule", "list")
+	assert(len(rules) > 0, "Rules should be listed", &errorCnt)
+	swmctl("rule", "clear")
+	rules, _ = swmctlOut("rule", "list")
+	assert(len(rules) == 0, "Rules should be cleared", &errorCnt)
+
+	// invalid rule is refused
+	out, _ := swmctlOut("rule", "add", "-layer", "sideways")
+	assert(len(out) > 0, "Invalid rule should be refused", &errorCnt)
+
+	return errorCnt
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
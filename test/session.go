@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/janbina/swm/internal/session"
+)
+
+func testSession() int {
+	errorCnt := 0
+
+	win := createWindow()
+	id := intStr(int(win.Id))
+	swmctl("group", "set", "-id", id, "-g", "1")
+	swmctl("group", "add", "-id", id, "-g", "2")
+
+	out, err := swmctlOut("session", "save")
+	assert(err == nil && out == "", "Session should be saved", &errorCnt)
+
+	path, _ := swmctlOut("session", "path")
+	s, err := session.Load(strings.TrimSpace(path))
+	if err != nil {
+		assert(false, "Cannot load saved session", &errorCnt)
+		win.Destroy()
+		return errorCnt
+	}
+
+	var saved *session.Window
+	for _, sw := range s.Windows {
+		if sw.Id == win.Id {
+			saved = sw
+		}
+	}
+	assert(saved != nil, "Window should be in saved session", &errorCnt)
+	if saved != nil {
+		assertSliceEquals([]int{1, 2}, uintsToInts(saved.Groups), "Invalid saved groups", &errorCnt)
+		g := geom(win)
+		assertEquals(g.Width(), saved.Geometry.Width, "Invalid saved width", &errorCnt)
+		assertEquals(g.Height(), saved.Geometry.Height, "Invalid saved height", &errorCnt)
+	}
+
+	win.Destroy()
+
+	return errorCnt
+}
+
+func uintsToInts(uints []uint) []int {
+	ints := make([]int, len(uints))
+	for i, u := range uints {
+		ints[i] = int(u)
+	}
+	return ints
+}
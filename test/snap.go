@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+)
+
+func testSnapAndSwap() int {
+	errorCnt := 0
+
+	_ = ewmh.NumberOfDesktopsReq(X, 2)
+	waitForPropertyChange(X.RootWin(), "_NET_NUMBER_OF_DESKTOPS")
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+
+	screen, _ := xwindow.New(X, X.RootWin()).Geometry()
+
+	wins := createWindows(2)
+	id0, id1 := intStr(int(wins[0].Id)), intStr(int(wins[1].Id))
+	swmctl("moveresize", "-id", id0, "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+	swmctl("moveresize", "-id", id1, "-x", "500", "-y", "150", "-w", "100", "-h", "100")
+
+	// snaps to the edge of the other window
+	swmctl("move", "-id", id1, "-snap", "west")
+	assertGeomEquals(xrect.New(300, 150, 100, 100), geom(wins[1]), "Window should snap to other window", &errorCnt)
+
+	// snaps to the screen edge
+	swmctl("move", "-id", id1, "-snap", "north")
+	assertGeomEquals(xrect.New(300, 0, 100, 100), geom(wins[1]), "Window should snap to screen edge", &errorCnt)
+	swmctl("move", "-id", id1, "-snap", "east")
+	assertGeomEquals(xrect.New(screen.Width()-100, 0, 100, 100), geom(wins[1]), "Window should snap to screen edge", &errorCnt)
+
+	// other window is not in the way anymore
+	swmctl("move", "-id", id0, "-snap", "north")
+	assertGeomEquals(xrect.New(100, 0, 200, 200), geom(wins[0]), "Window should snap to screen edge", &errorCnt)
+
+	swmctl("swap", "-id", id0, "-d", "east")
+	assertGeomEquals(xrect.New(screen.Width()-100, 0, 100, 100), geom(wins[0]), "Windows should swap geometry", &errorCnt)
+	assertGeomEquals(xrect.New(100, 0, 200, 200), geom(wins[1]), "Windows should swap geometry", &errorCnt)
+
+	out, _ := swmctlOut("swap", "-id", id0, "-d", "east")
+	assert(len(out) > 0, "Swapping with nonexistent window should fail", &errorCnt)
+
+	destroyWindows(wins)
+
+	// windows in the same distance are swapped in the order they were managed
+	wins = createWindows(3)
+	swmctl("moveresize", "-id", intStr(int(wins[0].Id)), "-x", "0", "-y", "300", "-w", "100", "-h", "100")
+	swmctl("moveresize", "-id", intStr(int(wins[1].Id)), "-x", "300", "-y", "200", "-w", "100", "-h", "100")
+	swmctl("moveresize", "-id", intStr(int(wins[2].Id)), "-x", "300", "-y", "400", "-w", "100", "-h", "100")
+	swmctl("swap", "-id", intStr(int(wins[0].Id)), "-d", "east")
+	assertGeomEquals(xrect.New(300, 200, 100, 100), geom(wins[0]), "Window should swap with the first managed one", &errorCnt)
+	assertGeomEquals(xrect.New(0, 300, 100, 100), geom(wins[1]), "Window should swap with the first managed one", &errorCnt)
+	destroyWindows(wins)
+
+	return errorCnt
+}
+
+func testDragSnapping() int {
+	errorCnt := 0
+
+	swmctl("group", "mode", "auto")
+	swmctl("group", "only", "0")
+	swmctl("config", "move-drag-shortcut", "Mod1-1")
+
+	out, _ := swmctlOut("config", "snap-distance", "-1")
+	assert(len(out) > 0, "Negative snap distance should be refused", &errorCnt)
+
+	screen, _ := xwindow.New(X, X.RootWin()).Geometry()
+
+	win := createWindow()
+	id := intStr(int(win.Id))
+
+	// without snap distance window stays where it was dropped
+	swmctl("moveresize", "-id", id, "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+	fakeDrag(200, 200, 105, 200, "Alt_L")
+	assertGeomEquals(xrect.New(5, 100, 200, 200), geom(win), "Window should not snap", &errorCnt)
+
+	// window dropped within snap distance snaps to the screen edge
+	swmctl("config", "snap-distance", "10")
+	swmctl("moveresize", "-id", id, "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+	fakeDrag(200, 200, 105, 200, "Alt_L")
+	assertGeomEquals(xrect.New(0, 100, 200, 200), geom(win), "Window should snap to screen edge", &errorCnt)
+
+	// window dragged to the screen edge is tiled to its half
+	swmctl("config", "edge-tiling", "true")
+	swmctl("moveresize", "-id", id, "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+	fakeDrag(200, 200, 0, screen.Height()/2, "Alt_L")
+	assertGeomEquals(xrect.New(0, 0, screen.Width()/2, screen.Height()), geom(win), "Window should be tiled to the left half", &errorCnt)
+
+	// and to its quarter in the corner
+	swmctl("moveresize", "-id", id, "-x", "100", "-y", "100", "-w", "200", "-h", "200")
+	fakeDrag(200, 200, screen.Width()-1, 0, "Alt_L")
+	assertGeomEquals(xrect.New(screen.Width()/2, 0, screen.Width()/2, screen.Height()/2), geom(win), "Window should be tiled to the top right quarter", &errorCnt)
+
+	swmctl("config", "edge-tiling", "false")
+	swmctl("config", "snap-distance", "0")
+	destroyWindows([]*xwindow.Window{win})
+
+	return errorCnt
+}
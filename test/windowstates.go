@@ -190,3 +190,60 @@ func testWindowStates() int {
 
 	return errorCnt
 }
+
+func testShading() int {
+	errorCnt := 0
+
+	win := createWindow()
+	id := intStr(int(win.Id))
+	initGeom := geom(win)
+	initExtents, _ := ewmh.FrameExtentsGet(X, win.Id)
+
+	// shading through client message leaves only decorations
+	_ = ewmh.WmStateReqExtra(X, win.Id, ewmh.StateAdd, "_NET_WM_STATE_SHADED", "", 2)
+	waitForPropertyChange(win.Id, "_NET_WM_STATE")
+	states, _ := ewmh.WmStateGet(X, win.Id)
+	assert(contains(states, "_NET_WM_STATE_SHADED"), "Window should be shaded", &errorCnt)
+	assertGeomEquals(
+		xrect.New(initGeom.X(), initGeom.Y(), initGeom.Width(), initExtents.Top+initExtents.Bottom),
+		geom(win),
+		"Shaded frame should contain only decorations",
+		&errorCnt,
+	)
+	extents, _ := ewmh.FrameExtentsGet(X, win.Id)
+	assert(extents != nil && *extents == *initExtents, "Frame extents should not change", &errorCnt)
+
+	// shaded window can be activated, frame holds the focus
+	other := createWindow()
+	_ = ewmh.ActiveWindowReq(X, other.Id)
+	assertActive(other, &errorCnt)
+	_ = ewmh.ActiveWindowReq(X, win.Id)
+	assertActive(win, &errorCnt)
+	// and loses it
+	_ = ewmh.ActiveWindowReq(X, other.Id)
+	assertActive(other, &errorCnt)
+	states, _ = ewmh.WmStateGet(X, win.Id)
+	assert(!contains(states, "_NET_WM_STATE_FOCUSED"), "Shaded window should not be focused", &errorCnt)
+	other.Destroy()
+
+	// unshading restores height
+	swmctl("shade", "-id", id, "toggle")
+	states, _ = ewmh.WmStateGet(X, win.Id)
+	assert(!contains(states, "_NET_WM_STATE_SHADED"), "Window should not be shaded", &errorCnt)
+	assertGeomEquals(initGeom, geom(win), "Geometry should be restored", &errorCnt)
+
+	// window moved while shaded keeps new position
+	swmctl("shade", "-id", id, "on")
+	swmctl("move", "-id", id, "-e", "10", "-s", "20")
+	swmctl("shade", "-id", id, "off")
+	assertGeomEquals(
+		xrect.New(initGeom.X()+10, initGeom.Y()+20, initGeom.Width(), initGeom.Height()),
+		geom(win),
+		"Shaded window should be movable",
+		&errorCnt,
+	)
+
+	win.Destroy()
+
+	return errorCnt
+}